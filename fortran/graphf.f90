@@ -22,6 +22,7 @@ module graphf
         procedure :: setOrder => addVertex_graph
         procedure :: addEdge => addEdge_graph
         procedure :: degree => degree_graph
+        procedure :: readMatrix => readMatrix_graph
 
     end type graph
 
@@ -163,6 +164,56 @@ function degree_graph(self, v, neighbours)
     return
 end function degree_graph
 
+!-- read a graph from a dense weight matrix file (as written by WriteMatrix in Go):
+!   comment lines starting with #, a header line with the number of vertices
+!   (and optionally "directed" or "undirected"), then one row of weights
+!   per vertex, where 0 means that there is no edge
+subroutine readMatrix_graph(self, fname)
+    class(graph) :: self
+    character(len=*),intent(in) :: fname
+    character(len=256) :: line
+    character(len=16) :: kind
+    integer :: u,io,n,i,j
+    open(newunit=u, file=fname, status='old', action='read', iostat=io)
+    if(io /= 0)then
+        error stop "could not open the matrix file!"
+    endif
+    !-- skip the comments until the header
+    do
+        read(u,'(a)',iostat=io) line
+        if(io /= 0)then
+            error stop "missing header in the matrix file!"
+        endif
+        line = adjustl(line)
+        if(len_trim(line) > 0 .and. line(1:1) /= '#') exit
+    enddo
+    read(line,*) n
+    kind = 'undirected'
+    if(index(line,' directed') > 0) kind = 'directed'
+
+    call self%deallocate()
+    self%directed = (kind == 'directed')
+    call self%setOrder(n)
+    !-- the rows of the weight matrix
+    do i=1,n
+        read(u,*,iostat=io) (self%emat(i,j), j=1,n)
+        if(io /= 0)then
+            error stop "invalid row in the matrix file!"
+        endif
+    enddo
+    close(u)
+    !-- the neighbour matrix follows from the weights
+    do i=1,n
+        do j=1,n
+            if(self%emat(i,j) /= 0.0_sp)then
+                self%nmat(i,j) = 1
+                if(self%directed .or. i < j) self%E = self%E + 1
+            endif
+        enddo
+    enddo
+    return
+end subroutine readMatrix_graph
+
 !=====================================================================================!
 
 subroutine example1(G)
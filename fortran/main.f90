@@ -3,12 +3,33 @@ program MAIN
     use graphf
     implicit none
     type(graph) :: G
-    integer :: start,end
+    integer :: start,end,ios
+    character(len=256) :: arg
 
     !-- set up an example
-    call example1(G)
     start = 1 !remember that arrays start at 1 in fortran
     end = 14
+    !-- or read the graph from a weight matrix file given as the first
+    !   argument (optionally followed by the start and end points)
+    if(command_argument_count() > 0)then
+        call get_command_argument(1, arg)
+        call G%readMatrix(trim(arg))
+        !-- by default from the first to the last vertex
+        end = G%V
+        if(command_argument_count() > 2)then
+            call get_command_argument(2, arg)
+            read(arg,*,iostat=ios) start
+            if(ios /= 0) error stop "invalid start point!"
+            call get_command_argument(3, arg)
+            read(arg,*,iostat=ios) end
+            if(ios /= 0) error stop "invalid end point!"
+        endif
+    else
+        call example1(G)
+    endif
+    if(start < 1 .or. start > G%V .or. end < 1 .or. end > G%V)then
+        error stop "start and end point must be vertices of the graph!"
+    endif
 
     !-- call the example with Dijkstra's Algorithm
     write(*,'(a,i0,a,i0,a)') "Shortest path from vertex ", start, " to vertex ", end, &
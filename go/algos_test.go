@@ -1,8 +1,13 @@
-package main
+package shortestpath
 
 import (
+	"errors"
+	"math"
 	"math/rand"
+	"strconv"
+	"strings"
 	"testing"
+	"unsafe"
 )
 
 /*
@@ -21,6 +26,20 @@ func BenchmarkDijkstra(b *testing.B) {
 	}
 }
 
+func BenchmarkDijkstraCSR(b *testing.B) {
+	// the same random sample graph as above, but in CSR format
+	L := RandomGraph(10000, 3)
+	C := CSRFromGraph(L)
+	start := 0
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Dijkstra(C, start)
+	}
+	b.ReportMetric(float64(matrixBytes(L)), "matrix-bytes")
+	b.ReportMetric(float64(csrBytes(C)), "csr-bytes")
+}
+
 func BenchmarkBellmanFord(b *testing.B) {
 	// set up a large random sample graph
 	// 1000 vertices, 3+ edges per vertex
@@ -33,6 +52,17 @@ func BenchmarkBellmanFord(b *testing.B) {
 	}
 }
 
+func BenchmarkBellmanFordCSR(b *testing.B) {
+	L := RandomGraph(1000, 3)
+	C := CSRFromGraph(L)
+	start := 0
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = BellmanFord(C, start)
+	}
+}
+
 func BenchmarkFloydWarshall(b *testing.B) {
 	// set up a large random sample graph
 	// 1000 vertices, 3+ edges per vertex
@@ -49,9 +79,10 @@ func TestDijkstraFibonacciHeap(t *testing.T) {
 	G := RandomGraph(500, 2)
 	start := 0
 	dist, prev := DijkstraFibonacci(G, start)
-	path := make([]int, 0, G.V)
-	path, _ = getPathD(&start, 499, prev, path)
-	// fmt.Println(path)
+	path, err := GetPathD(start, 499, prev)
+	if err != nil || path[0] != start || path[len(path)-1] != 499 {
+		t.Errorf("Path to test node 499 incorrect, got %v (%v)", path, err)
+	}
 	if dist[269] != 7.0 {
 		t.Errorf("Distance to test node 269 incorrect, got %f, want %f", dist[269], 7.0)
 	}
@@ -72,3 +103,599 @@ func BenchmarkDijkstraFibonacciHeap(b *testing.B) {
 		_, _ = DijkstraFibonacci(G, start)
 	}
 }
+
+func BenchmarkDijkstraFibonacciHeapCSR(b *testing.B) {
+	G := RandomGraph(10000, 3)
+	C := CSRFromGraph(G)
+	start := 0
+
+	b.ResetTimer()
+	for j := 0; j < b.N; j++ {
+		_, _ = DijkstraFibonacci(C, start)
+	}
+}
+
+func BenchmarkDijkstraFibonacciHeapCSRLarge(b *testing.B) {
+	// 100000 vertices, 3+ edges per vertex.
+	// The matrix form of this graph would need ~160 GB.
+	n := 100000
+	edges := make([]Edge, 0, 3*n)
+	for i := 0; i < n; i++ {
+		for k := 0; k < 3; k++ {
+			edges = append(edges, Edge{i, rand.Intn(n), 1.0})
+		}
+	}
+	C := NewCSR(n, false, edges)
+	start := 0
+
+	b.ResetTimer()
+	for j := 0; j < b.N; j++ {
+		_, _ = DijkstraFibonacci(C, start)
+	}
+	b.ReportMetric(float64(n)*float64(n)*16, "matrix-bytes")
+	b.ReportMetric(float64(csrBytes(C)), "csr-bytes")
+}
+
+// the CSR graph must give the same results as the matrix form
+func TestCSR(t *testing.T) {
+	G := NewGraph()
+	G.Example2()
+	C := CSRFromGraph(G)
+	if C.V != G.V || C.E != G.E {
+		t.Fatalf("CSR graph has %d vertices and %d edges, want %d and %d", C.V, C.E, G.V, G.E)
+	}
+	if !C.HasEdge(13, 11) || C.Weight(13, 11) != G.Weight(11, 13) || C.HasEdge(0, 2) {
+		t.Errorf("CSR graph edges incorrect")
+	}
+	start := 0
+	algos := map[string]func(Interface, int) ([]float64, []int){
+		"Dijkstra":          Dijkstra,
+		"DijkstraFibonacci": DijkstraFibonacci,
+		"BellmanFord":       BellmanFord,
+	}
+	for name, algo := range algos {
+		dist, _ := algo(G, start)
+		distC, prevC := algo(C, start)
+		for v := range dist {
+			if dist[v] != distC[v] {
+				t.Errorf("%s: distance to node %d incorrect, got %f, want %f", name, v, distC[v], dist[v])
+			}
+		}
+		path, err := GetPathD(start, 13, prevC)
+		if err != nil || len(path) != 8 {
+			t.Errorf("%s: path to node 13 incorrect, got %v (%v)", name, path, err)
+		}
+	}
+
+	// parallel edges are avoided and the first weight is kept
+	C = NewCSR(3, false, []Edge{{0, 1, 2.0}, {1, 0, 1.0}, {1, 2, 1.0}, {2, 5, 1.0}})
+	if C.E != 2 || C.Weight(0, 1) != 2.0 || C.Degree(1) != 2 {
+		t.Errorf("CSR graph from edge list incorrect, got %d edges", C.E)
+	}
+}
+
+// memory needed for the adjacency and weight matrices of a Graph
+func matrixBytes(G *Graph) int {
+	return G.V * G.V * int(unsafe.Sizeof(int(0))+unsafe.Sizeof(float64(0)))
+}
+
+// memory needed for the offsets, targets and weights of a CSR graph
+func csrBytes(C *CSR) int {
+	return (len(C.Offsets)+len(C.Targets))*int(unsafe.Sizeof(int(0))) + len(C.Weights)*int(unsafe.Sizeof(float64(0)))
+}
+
+// grid is an implicit graph (a n x n square grid with unit weights)
+// that is never stored, used to test the Interface
+type grid struct {
+	n int
+}
+
+func (g grid) Order() int { return g.n * g.n }
+
+func (g grid) VisitEdges(u int, fn func(v int, w float64)) {
+	x, y := u%g.n, u/g.n
+	if x > 0 {
+		fn(u-1, 1.0)
+	}
+	if x < g.n-1 {
+		fn(u+1, 1.0)
+	}
+	if y > 0 {
+		fn(u-g.n, 1.0)
+	}
+	if y < g.n-1 {
+		fn(u+g.n, 1.0)
+	}
+}
+
+// all algorithms must run on a custom Interface implementation
+func TestInterface(t *testing.T) {
+	G := grid{8}
+	start := 0
+	want := func(v int) float64 { return float64(v%G.n + v/G.n) } // manhattan distance
+	algos := map[string]func(Interface, int) ([]float64, []int){
+		"Dijkstra":          Dijkstra,
+		"DijkstraFibonacci": DijkstraFibonacci,
+		"BellmanFord":       BellmanFord,
+	}
+	for name, algo := range algos {
+		dist, prev := algo(G, start)
+		for v := range dist {
+			if dist[v] != want(v) {
+				t.Errorf("%s: distance to node %d incorrect, got %f, want %f", name, v, dist[v], want(v))
+			}
+		}
+		path, err := GetPathD(start, G.Order()-1, prev)
+		if err != nil || len(path) != 2*G.n-1 {
+			t.Errorf("%s: path to the last node incorrect, got %v (%v)", name, path, err)
+		}
+	}
+	dist, prev := FloydWarshall(G)
+	for v := range dist[start] {
+		if v != start && dist[start][v] != want(v) {
+			t.Errorf("FloydWarshall: distance to node %d incorrect, got %f, want %f", v, dist[start][v], want(v))
+		}
+	}
+	if path := GetPathFW(prev, start, G.Order()-1); len(path) != 2*G.n-1 {
+		t.Errorf("FloydWarshall: path to the last node incorrect, got %v", path)
+	}
+}
+
+// directedExample2 sets up the graph of Example2 as a directed
+// graph, where all edges point from the lower to the higher vertex
+func directedExample2() *Graph {
+	U := NewGraph()
+	U.Example2()
+	G := NewDirectedGraph()
+	G.SetOrder(U.V)
+	for e := range U.Edges() { // From <= To for undirected edges
+		G.AddEdge(e.From, e.To, e.Weight)
+	}
+	return G
+}
+
+// all four algorithms on directed graphs
+func TestDirected(t *testing.T) {
+	G := directedExample2()
+	want := map[int]float64{4: 1.36, 8: 2.27, 3: 5.50, 10: 7.42, 12: 7.92, 13: 10.29, 14: 9.18, 15: 10.30}
+	algos := map[string]func(Interface, int) ([]float64, []int){
+		"Dijkstra":          Dijkstra,
+		"DijkstraFibonacci": DijkstraFibonacci,
+		"BellmanFord":       BellmanFord,
+		"FloydWarshall": func(G Interface, start int) ([]float64, []int) {
+			dist, _ := FloydWarshall(G)
+			return dist[start], nil
+		},
+	}
+	for name, algo := range algos {
+		for _, g := range []Interface{G, CSRFromGraph(G)} {
+			dist, prev := algo(g, 0)
+			for v, d := range want {
+				if math.Abs(dist[v]-d) > 1e-5 {
+					t.Errorf("%s: distance to node %d incorrect, got %f, want %f", name, v, dist[v], d)
+				}
+			}
+			if prev != nil {
+				if path, err := GetPathD(0, 13, prev); err != nil || len(path) != 8 || path[2] != 2 {
+					t.Errorf("%s: path to node 13 incorrect, got %v (%v)", name, path, err)
+				}
+			}
+			// the edges can't be walked backwards
+			dist, _ = algo(g, 15)
+			if !math.IsInf(dist[0], 0) {
+				t.Errorf("%s: node 0 should not be reachable from node 15, got %f", name, dist[0])
+			}
+		}
+	}
+
+	// a directed cycle with a shortcut only in one direction
+	G = NewDirectedGraph()
+	G.SetOrder(3)
+	G.AddEdge(0, 1, 1.0)
+	G.AddEdge(1, 2, 1.0)
+	G.AddEdge(2, 0, 1.0)
+	G.AddEdge(0, 2, 5.0)
+	dist, prev := FloydWarshall(G)
+	if dist[0][2] != 2.0 || dist[2][1] != 2.0 || dist[1][0] != 2.0 {
+		t.Errorf("FloydWarshall: directed cycle distances incorrect, got %v", dist)
+	}
+	if path := GetPathFW(prev, 2, 1); len(path) != 3 || path[1] != 0 {
+		t.Errorf("FloydWarshall: path from 2 to 1 incorrect, got %v", path)
+	}
+}
+
+func TestNegativeCycle(t *testing.T) {
+	G := NewDirectedGraph()
+	G.SetOrder(4)
+	G.AddEdge(0, 1, 1.0)
+	G.AddEdge(1, 2, -2.0)
+	G.AddEdge(2, 3, 1.0)
+	if _, _, err := BellmanFordCheck(G, 0); err != nil {
+		t.Errorf("BellmanFordCheck without a cycle incorrect, got %v, want nil", err)
+	}
+	G.AddEdge(2, 1, 1.0)
+	if _, _, err := BellmanFordCheck(G, 0); !errors.Is(err, ErrNegativeCycle) {
+		t.Errorf("BellmanFordCheck incorrect, got %v, want %v", err, ErrNegativeCycle)
+	}
+	// the cycle can't be reached from vertex 3
+	if _, _, err := BellmanFordCheck(CSRFromGraph(G), 3); err != nil {
+		t.Errorf("BellmanFordCheck from vertex 3 incorrect, got %v, want nil", err)
+	}
+}
+
+// integer weights must give exact distances
+func TestIntegerWeights(t *testing.T) {
+	big := int64(1) << 53 // float64 can't represent big+1
+	C := NewCSROf(4, true, []EdgeOf[int64]{{0, 1, big}, {1, 2, 1}, {0, 2, big + 2}, {3, 0, 1}})
+	dist, prev := DijkstraOf[int64](C, 0)
+	distBF, _ := BellmanFordOf[int64](C, 0)
+	distFW, _ := FloydWarshallOf[int64](C)
+	for _, d := range [][]int64{dist, distBF, distFW[0]} {
+		if d[2] != big+1 || d[3] != Infinity[int64]() {
+			t.Errorf("integer distances incorrect, got %v", d)
+		}
+	}
+	if path, _ := GetPathD(0, 2, prev); len(path) != 3 {
+		t.Errorf("path to node 2 incorrect, got %v", path)
+	}
+	if Infinity[int32]() != math.MaxInt32 || !math.IsInf(float64(Infinity[float32]()), 1) {
+		t.Errorf("Infinity incorrect")
+	}
+
+	// the weights of Example2 are stored without rounding
+	G := NewGraph()
+	G.Example2()
+	dist64, _ := Dijkstra(G, 0)
+	want := 0.0
+	for _, l := range []float64{1.36, 0.55, 0.80, 1.12, 0.50, 0.45, 1.87} {
+		want += l
+	}
+	if dist64[13] != want {
+		t.Errorf("distance to node 13 incorrect, got %v, want %v", dist64[13], want)
+	}
+}
+
+// shortest paths with different costs over the same edges
+func TestAttributed(t *testing.T) {
+	A := NewAttributed(3, false, "length", "time", "toll")
+	highway, _ := A.AddEdge(0, 1, 12.0, 6.0, 4.0)
+	road, _ := A.AddEdge(0, 1, 9.0, 10.0, 0.0)
+	A.AddEdge(1, 2, 3.0, 2.0, 0.0)
+	if _, err := A.AddEdge(1, 2, 3.0, 2.0); !errors.Is(err, ErrAttributes) {
+		t.Errorf("AddEdge with missing attributes returned %v", err)
+	}
+
+	length, _ := A.Select("length")
+	time, _ := A.Select("time")
+	price, _ := A.Combine(map[string]float64{"time": 1.0, "toll": 2.0})
+	if _, err := A.Select("class"); !errors.Is(err, ErrAttributes) {
+		t.Errorf("Select of an unknown attribute returned %v", err)
+	}
+	tests := []struct {
+		cost CostFunc
+		dist float64
+		edge int
+	}{
+		{length, 12.0, road},
+		{time, 8.0, highway},
+		{price, 12.0, road},
+		{func(a []float64) float64 { // no toll roads
+			if a[2] > 0 {
+				return math.Inf(1)
+			}
+			return a[1]
+		}, 12.0, road},
+	}
+	for i, tt := range tests {
+		C := A.Cost(tt.cost)
+		for _, algo := range []func(Interface, int) ([]float64, []int){Dijkstra, BellmanFord} {
+			dist, prev := algo(C, 0)
+			path, _ := GetPathD(0, 2, prev)
+			ids, err := C.PathEdges(path)
+			if dist[2] != tt.dist || err != nil || ids[0] != tt.edge {
+				t.Errorf("cost %d: path incorrect, got distance %f and edges %v", i, dist[2], ids)
+			}
+		}
+	}
+	// all edges between 0 and 1 are excluded
+	closed := A.Cost(func(a []float64) float64 {
+		if a[0] > 5 {
+			return math.Inf(1)
+		}
+		return a[0]
+	})
+	if ids, err := closed.PathEdges([]int{0, 1, 2}); !errors.Is(err, ErrMissingEdge) {
+		t.Errorf("PathEdges over excluded edges incorrect, got %v (%v), want %v", ids, err, ErrMissingEdge)
+	}
+	// the multigraph can only be changed together with the attributes
+	if ids := A.EdgesBetween(0, 1); A.Size() != 3 || len(ids) != 2 || A.Edge(ids[1]).Weight != 9.0 {
+		t.Errorf("edges between 0 and 1 incorrect, got %v", ids)
+	}
+	if toll, _ := A.Attr(highway, "toll"); toll != 4.0 {
+		t.Errorf("toll of the highway incorrect, got %f", toll)
+	}
+}
+
+// shortest paths on filtered views of a graph
+func TestView(t *testing.T) {
+	G := NewGraph()
+	G.Example2()
+	// closing vertex 3 makes the path from 0 to 13 longer
+	dist, prev := Dijkstra(Without[float64](G, 3), 0)
+	path, _ := GetPathD(0, 13, prev)
+	for _, v := range path {
+		if v == 3 {
+			t.Errorf("path runs over the hidden vertex 3: %v", path)
+		}
+	}
+	if distFull, _ := Dijkstra(G, 0); dist[13] <= distFull[13] || !math.IsInf(dist[3], 1) {
+		t.Errorf("distance without vertex 3 incorrect, got %f", dist[13])
+	}
+	// only short edges
+	V := Filter(CSRFromGraph(G), nil, func(u, v int, w float64) bool { return w < 1.5 })
+	for name, algo := range map[string]func(Interface, int) ([]float64, []int){"DijkstraFibonacci": DijkstraFibonacci, "BellmanFord": BellmanFord} {
+		dist, _ := algo(V, 0)
+		if dist[4] != 1.36 || !math.IsInf(dist[1], 1) {
+			t.Errorf("%s: distances with short edges only incorrect, got %v", name, dist)
+		}
+	}
+	if dist, _ := FloydWarshall(V); !math.IsInf(dist[0][1], 1) {
+		t.Errorf("FloydWarshall: distances with short edges only incorrect")
+	}
+}
+
+// shortest paths from all vertices to a single target
+func TestTarget(t *testing.T) {
+	G := directedExample2()
+	T := G.Transpose()
+	if T.E != G.E || !T.HasEdge(13, 11) || T.HasEdge(11, 13) || T.Weight(13, 11) != G.Weight(11, 13) {
+		t.Errorf("transposed graph incorrect")
+	}
+	C := CSRFromGraph(G).Transpose()
+	if C.E != G.E || !C.HasEdge(13, 11) || C.HasEdge(11, 13) {
+		t.Errorf("transposed CSR graph incorrect")
+	}
+
+	target := 13
+	for name, algo := range map[string]func(Interface, int) ([]float64, []int){"DijkstraTo": DijkstraTo, "BellmanFordTo": BellmanFordTo} {
+		dist, next := algo(G, target)
+		for v := 0; v < G.V; v++ {
+			want, _ := Dijkstra(G, v)
+			// the sums are added up in reverse order, so allow for rounding
+			if math.Abs(dist[v]-want[target]) > 1e-12 && !(math.IsInf(dist[v], 1) && math.IsInf(want[target], 1)) {
+				t.Errorf("%s: distance from node %d incorrect, got %f, want %f", name, v, dist[v], want[target])
+			}
+		}
+		path, err := GetPathTo(0, target, next)
+		if err != nil || len(path) != 8 || path[0] != 0 || path[1] != 1 || path[7] != target {
+			t.Errorf("%s: path from node 0 incorrect, got %v (%v)", name, path, err)
+		}
+		if _, err := GetPathTo(15, target, next); err == nil {
+			t.Errorf("%s: path from node 15 should not exist", name)
+		}
+	}
+}
+
+// connected and strongly connected components
+func TestComponents(t *testing.T) {
+	G := NewGraph()
+	G.Example1()
+	G.AddVertices(3)
+	G.AddEdge(16, 17, 1.0)
+	comp, n := Components[float64](G)
+	if n != 3 || comp[0] != 0 || comp[15] != 0 || comp[16] != 1 || comp[17] != 1 || comp[18] != 2 {
+		t.Errorf("connected components incorrect, got %d components %v", n, comp)
+	}
+	L, remap := G.LargestComponent()
+	if L.V != 16 || L.E != 21 || remap[16] != -1 || remap[15] != 15 {
+		t.Errorf("largest component incorrect, got %d vertices", L.V)
+	}
+
+	// two directed cycles connected by a single edge
+	D := NewDirectedGraph()
+	D.SetOrder(6)
+	D.AddEdge(0, 1, 1.0)
+	D.AddEdge(1, 2, 1.0)
+	D.AddEdge(2, 0, 1.0)
+	D.AddEdge(2, 3, 1.0)
+	D.AddEdge(3, 4, 1.0)
+	D.AddEdge(4, 3, 1.0)
+	for _, g := range []Interface{D, CSRFromGraph(D)} {
+		comp, n = StronglyConnected(g)
+		if n != 3 || comp[0] != comp[1] || comp[1] != comp[2] || comp[3] != comp[4] || comp[2] == comp[3] || comp[5] == comp[3] {
+			t.Errorf("strongly connected components incorrect, got %d components %v", n, comp)
+		}
+		// reverse topological order: the cycle {3,4} can't reach {0,1,2}
+		if comp[3] > comp[0] {
+			t.Errorf("strongly connected components not in reverse topological order: %v", comp)
+		}
+		if _, n = Components(g); n != 2 {
+			t.Errorf("weakly connected components incorrect, got %d components", n)
+		}
+	}
+	L, remap = D.LargestComponent()
+	if L.V != 3 || L.E != 3 || !L.Directed() || remap[3] != -1 {
+		t.Errorf("largest strongly connected component incorrect, got %d vertices", L.V)
+	}
+
+	// a long path must not overflow the stack
+	m := 100000
+	edges := make([]Edge, 0, m)
+	for i := 0; i < m; i++ {
+		edges = append(edges, Edge{i, (i + 1) % m, 1.0})
+	}
+	C := NewCSR(m, true, edges)
+	if _, n = StronglyConnected[float64](C); n != 1 {
+		t.Errorf("strongly connected components of a cycle incorrect, got %d components", n)
+	}
+}
+
+// topological order and paths in directed acyclic graphs
+func TestDAG(t *testing.T) {
+	// a small build pipeline with durations as (negative) weights
+	G := NewDirectedGraph()
+	G.SetOrder(6)
+	G.AddEdge(0, 1, 3.0)
+	G.AddEdge(0, 2, 2.0)
+	G.AddEdge(1, 3, -4.0)
+	G.AddEdge(2, 3, 1.0)
+	G.AddEdge(3, 4, 2.0)
+	G.AddEdge(1, 4, 7.0)
+
+	order, err := TopologicalSort[float64](G)
+	pos := make([]int, G.V)
+	for i, v := range order {
+		pos[v] = i
+	}
+	if err != nil || len(order) != G.V {
+		t.Fatalf("TopologicalSort failed: %v", err)
+	}
+	for e := range G.Edges() {
+		if pos[e.From] > pos[e.To] {
+			t.Errorf("edge %v is against the topological order %v", e, order)
+		}
+	}
+	if cycle := FindCycle[float64](G); cycle != nil {
+		t.Errorf("FindCycle found a cycle in a DAG: %v", cycle)
+	}
+
+	dist, prev, err := DAGShortest[float64](G, 0)
+	want, _ := BellmanFord(G, 0)
+	for v := range dist {
+		if dist[v] != want[v] {
+			t.Errorf("DAGShortest: distance to node %d incorrect, got %f, want %f", v, dist[v], want[v])
+		}
+	}
+	if path, _ := GetPathD(0, 4, prev); err != nil || len(path) != 4 || path[1] != 1 {
+		t.Errorf("DAGShortest: path to node 4 incorrect, got %v (%v)", path, err)
+	}
+	dist, prev, _ = DAGLongest[float64](G, 0)
+	if path, _ := GetPathD(0, 4, prev); dist[4] != 10.0 || len(path) != 3 || !math.IsInf(dist[5], -1) {
+		t.Errorf("DAGLongest: critical path to node 4 incorrect, got %v with length %f", path, dist[4])
+	}
+
+	// integer weights
+	C := NewCSROf(3, true, []EdgeOf[int64]{{0, 1, 2}, {1, 2, -5}, {0, 2, 1}})
+	if dist, _, _ := DAGShortest(C, 0); dist[2] != -3 {
+		t.Errorf("DAGShortest: integer distance incorrect, got %d", dist[2])
+	}
+	if dist, _, _ := DAGLongest(C, 1); dist[2] != -5 || dist[0] != -Infinity[int64]() {
+		t.Errorf("DAGLongest: integer distances incorrect, got %v", dist)
+	}
+
+	// closing a cycle
+	G.AddEdge(4, 1, 1.0)
+	if _, err := TopologicalSort[float64](G); !errors.Is(err, ErrCycle) {
+		t.Errorf("TopologicalSort of a cyclic graph returned %v", err)
+	}
+	if _, _, err := DAGShortest[float64](G, 0); !errors.Is(err, ErrCycle) {
+		t.Errorf("DAGShortest of a cyclic graph returned %v", err)
+	}
+	cycle := FindCycle[float64](G)
+	if len(cycle) != 3 && len(cycle) != 2 {
+		t.Fatalf("FindCycle failed, got %v", cycle)
+	}
+	for i, v := range cycle {
+		if !G.HasEdge(v, cycle[(i+1)%len(cycle)]) {
+			t.Errorf("FindCycle returned an invalid cycle %v", cycle)
+		}
+	}
+}
+
+// eccentricity, diameter, radius, etc. of the example graph
+func TestMetrics(t *testing.T) {
+	G := NewGraph()
+	G.Example1()
+	dist, _ := FloydWarshall(G)
+	for _, M := range []*Metrics{MetricsFromDistances(dist), GraphMetrics(G), GraphMetrics(CSRFromGraph(G))} {
+		if M.Diameter != 6.0 || M.Radius != 3.0 || M.Eccentricity[0] != 6.0 || M.Eccentricity[2] != 4.0 {
+			t.Errorf("diameter or radius incorrect, got %f and %f", M.Diameter, M.Radius)
+		}
+		if len(M.Center) != 1 || M.Center[0] != 5 {
+			t.Errorf("center incorrect, got %v", M.Center)
+		}
+		if len(M.Periphery) != 3 || M.Periphery[0] != 0 || M.Periphery[1] != 13 || M.Periphery[2] != 15 {
+			t.Errorf("periphery incorrect, got %v", M.Periphery)
+		}
+		if M.Pairs != 16*15 || math.Abs(M.AverageLength-2.775) > 1e-12 {
+			t.Errorf("average shortest path length incorrect, got %f over %d pairs", M.AverageLength, M.Pairs)
+		}
+	}
+
+	// disconnected graphs have an infinite diameter
+	G.AddVertex()
+	M := GraphMetrics(G)
+	if !math.IsInf(M.Diameter, 1) || M.Radius != M.Diameter || M.Pairs != 16*15 {
+		t.Errorf("metrics of a disconnected graph incorrect, got diameter %f", M.Diameter)
+	}
+}
+
+// reading DIMACS challenge files and replaying their queries
+func TestDIMACS(t *testing.T) {
+	gr := `c a small directed graph
+p sp 4 5
+a 1 2 3
+a 2 3 4
+a 1 3 9
+a 3 4 1
+a 1 3 8
+`
+	G, err := ReadDIMACS(strings.NewReader(gr))
+	if err != nil || G.V != 4 || G.E != 4 || !G.Directed() || G.Weight(0, 2) != 8 || G.HasEdge(1, 0) {
+		t.Fatalf("reading the graph incorrect, got %v (%v)", G, err)
+	}
+	co, err := ReadDIMACSCoordinates(strings.NewReader("p aux sp co 4\nv 1 -73530767 41085396\nv 4 2 3\n"))
+	if err != nil || len(co) != 4 || co[0] != (Point{-73530767, 41085396}) || co[3] != (Point{2, 3}) {
+		t.Errorf("reading the coordinates incorrect, got %v (%v)", co, err)
+	}
+
+	p2p, err := ReadDIMACSQueries(strings.NewReader("p aux sp p2p 2\nq 1 4\nq 2 1\n"))
+	if err != nil || len(p2p) != 2 || p2p[0] != (Query{0, 3}) {
+		t.Fatalf("reading the point-to-point queries incorrect, got %v (%v)", p2p, err)
+	}
+	ss, err := ReadDIMACSQueries(strings.NewReader("c sources\np aux sp ss 1\ns 2\n"))
+	if err != nil || len(ss) != 1 || ss[0] != (Query{1, -1}) {
+		t.Fatalf("reading the single-source queries incorrect, got %v (%v)", ss, err)
+	}
+	for _, solve := range []Solver{Dijkstra, DijkstraFibonacci, BellmanFord} {
+		results, _, err := ReplayQueries(G, append(p2p, ss...), solve)
+		if err != nil || results[0] != 8 || !math.IsInf(results[1], 1) || results[2] != 4+5 {
+			t.Errorf("results of the queries incorrect, got %v (%v)", results, err)
+		}
+	}
+
+	var tests = []struct {
+		input string
+		line  int
+	}{
+		{"a 1 2 3\n", 1},
+		{"p sp 2 1\na 1 3 1\n", 2},
+		{"c\np sp 2 1\na 1 2 x\n", 3},
+		{"p sp 2 1\nx 1 2\n", 2},
+		{"p aux sp ss 1\nq 1 2\n", 2},
+		{"c huge graph\np sp " + strconv.Itoa(MaxCSROrder+1) + " 1\n", 2},
+	}
+	for _, tt := range tests {
+		_, err := ReadDIMACS(strings.NewReader(tt.input))
+		if strings.HasPrefix(tt.input, "p aux") {
+			_, err = ReadDIMACSQueries(strings.NewReader(tt.input))
+		}
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Line != tt.line {
+			t.Errorf("reading %q incorrect, got %v, want an error in line %d", tt.input, err, tt.line)
+		}
+	}
+	if _, err := ReadDIMACS(strings.NewReader("p sp 2 2\na 1 2 1\n")); err == nil {
+		t.Errorf("missing arcs were not reported")
+	}
+	// the counts of the problem lines must not be trusted
+	if _, err := ReadDIMACS(strings.NewReader("p sp 1 2147483647\n")); err == nil {
+		t.Errorf("missing arcs were not reported")
+	}
+	if _, err := ReadDIMACSQueries(strings.NewReader("p aux sp ss 2147483647\ns 1\n")); err == nil {
+		t.Errorf("missing queries were not reported")
+	}
+	if _, err := ReadDIMACSCoordinates(strings.NewReader("p aux sp co " + strconv.Itoa(MaxCSROrder+1) + "\n")); !errors.Is(err, ErrOrder) {
+		t.Errorf("too many coordinates incorrect, got %v, want %v", err, ErrOrder)
+	}
+}
@@ -0,0 +1,206 @@
+/* This file contains a graph in which each edge carries
+several attributes (e.g. length, travel time, toll, road class)
+instead of a single weight. Which attributes are used (or how
+they are combined) is decided for each query by a cost function. */
+
+package shortestpath
+
+import (
+	"fmt"
+	"iter"
+	"math"
+)
+
+// Attributed is a multigraph with named attributes for each edge.
+// The edge weights seen by the algorithms are equal to the
+// first attribute, other costs are chosen with Cost.
+type Attributed struct {
+	m      *Multigraph    // the edges, only added together with their attributes
+	names  []string       // names of the attributes
+	index  map[string]int // position of each attribute
+	attrs  []float64      // attributes of all edges, edge ID i at [i*k:(i+1)*k]
+	labels *Labels        // external identifiers of the vertices
+}
+
+// CostFunc calculates the cost (weight) of an edge from its attributes,
+// which are in the order of the names given to NewAttributed.
+// A cost of +Inf excludes the edge from the search.
+type CostFunc func(attrs []float64) float64
+
+// NewAttributed returns a multigraph with V vertices and no edges,
+// where each edge has the attributes with the given names
+func NewAttributed(V int, directed bool, names ...string) *Attributed {
+	A := new(Attributed)
+	A.m = NewMultigraph(V, directed)
+	A.names = names
+	A.index = make(map[string]int)
+	for i, name := range names {
+		A.index[name] = i
+	}
+	return A
+}
+
+// Names returns the names of the edge attributes
+func (A *Attributed) Names() []string {
+	return A.names
+}
+
+// AddEdge adds a new edge with the given attributes between two
+// vertices and returns its ID. There must be one (finite) value
+// for each attribute name.
+func (A *Attributed) AddEdge(v1, v2 int, attrs ...float64) (int, error) {
+	if len(attrs) != len(A.names) {
+		return -1, &EdgeError{"AddEdge", v1, v2, ErrAttributes}
+	}
+	for _, a := range attrs {
+		if math.IsNaN(a) || math.IsInf(a, 0) {
+			return -1, &EdgeError{"AddEdge", v1, v2, ErrWeight}
+		}
+	}
+	var l float64
+	if len(attrs) > 0 {
+		l = attrs[0]
+	}
+	id, err := A.m.AddEdge(v1, v2, l)
+	if err != nil {
+		return id, err
+	}
+	A.attrs = append(A.attrs, attrs...)
+	return id, nil
+}
+
+// Order returns the number of vertices (order of the graph)
+func (A *Attributed) Order() int { return A.m.V }
+
+// Size returns the number of edges (size of the graph)
+func (A *Attributed) Size() int { return A.m.E }
+
+// Directed reports whether A is a directed graph
+func (A *Attributed) Directed() bool { return A.m.directed }
+
+// HasEdge runs a check on a graph: is the edge with the given ID part of the graph?
+func (A *Attributed) HasEdge(id int) bool { return A.m.HasEdge(id) }
+
+// Edge returns the edge with the given ID, its weight is the first attribute
+func (A *Attributed) Edge(id int) Edge { return A.m.Edges[id] }
+
+// EdgeIDs returns an iterator over the IDs and the edges of the graph
+func (A *Attributed) EdgeIDs() iter.Seq2[int, Edge] {
+	return func(yield func(int, Edge) bool) {
+		for id, e := range A.m.Edges {
+			if A.m.HasEdge(id) && !yield(id, e) {
+				return
+			}
+		}
+	}
+}
+
+// EdgesBetween returns the IDs of all (parallel) edges from v1 to v2
+func (A *Attributed) EdgesBetween(v1, v2 int) []int { return A.m.EdgesBetween(v1, v2) }
+
+// VisitEdges calls fn for every edge leaving vertex u with
+// the neighbour v and the first attribute as weight
+func (A *Attributed) VisitEdges(u int, fn func(v int, w float64)) { A.m.VisitEdges(u, fn) }
+
+// VisitEdgeIDs is like VisitEdges, but also passes the ID of each edge to fn
+func (A *Attributed) VisitEdgeIDs(u int, fn func(v int, w float64, id int)) { A.m.VisitEdgeIDs(u, fn) }
+
+// PathEdges returns the IDs of the edges a path runs over,
+// choosing the edge with the smallest first attribute, see CostView.PathEdges otherwise
+func (A *Attributed) PathEdges(path []int) ([]int, error) { return A.m.PathEdges(path) }
+
+// TreeEdges returns for every vertex v the ID of the edge from prev[v] to v,
+// see Multigraph.TreeEdges
+func (A *Attributed) TreeEdges(prev []int) []int { return A.m.TreeEdges(prev) }
+
+// Attrs returns all attributes of the edge with the given ID.
+// The slice must not be modified.
+func (A *Attributed) Attrs(id int) []float64 {
+	k := len(A.names)
+	return A.attrs[id*k : (id+1)*k]
+}
+
+// Attr returns a single attribute of the edge with the given ID
+func (A *Attributed) Attr(id int, name string) (float64, error) {
+	i, ok := A.index[name]
+	if !ok {
+		return 0, fmt.Errorf("attribute %q: %w", name, ErrAttributes)
+	}
+	return A.Attrs(id)[i], nil
+}
+
+// Select returns a cost function that uses a single attribute as cost
+func (A *Attributed) Select(name string) (CostFunc, error) {
+	i, ok := A.index[name]
+	if !ok {
+		return nil, fmt.Errorf("attribute %q: %w", name, ErrAttributes)
+	}
+	return func(attrs []float64) float64 { return attrs[i] }, nil
+}
+
+// Combine returns a cost function that is the weighted sum of
+// several attributes, e.g. {"time": 1.0, "toll": 0.2}
+func (A *Attributed) Combine(factors map[string]float64) (CostFunc, error) {
+	f := make([]float64, len(A.names))
+	for name, x := range factors {
+		i, ok := A.index[name]
+		if !ok {
+			return nil, fmt.Errorf("attribute %q: %w", name, ErrAttributes)
+		}
+		f[i] = x
+	}
+	return func(attrs []float64) float64 {
+		c := 0.0
+		for i, a := range attrs {
+			c += f[i] * a
+		}
+		return c
+	}, nil
+}
+
+// Cost returns a view of the graph in which the weight of each
+// edge is given by the cost function. The view implements Interface,
+// so it can be passed to Dijkstra, BellmanFord, etc.
+func (A *Attributed) Cost(cost CostFunc) *CostView {
+	return &CostView{A, cost}
+}
+
+// CostView is a graph with attributed edges seen through a cost function
+type CostView struct {
+	A    *Attributed
+	cost CostFunc
+}
+
+// Order returns the number of vertices (order of the graph)
+func (C *CostView) Order() int { return C.A.m.V }
+
+// VisitEdges calls fn for every edge leaving vertex u with
+// the neighbour v and the cost of the edge. Edges with
+// an infinite cost are skipped.
+func (C *CostView) VisitEdges(u int, fn func(v int, w float64)) {
+	C.A.VisitEdgeIDs(u, func(v int, _ float64, id int) {
+		if w := C.cost(C.A.Attrs(id)); !math.IsInf(w, 1) {
+			fn(v, w)
+		}
+	})
+}
+
+// PathEdges returns the IDs of the edges a path runs over, choosing the
+// edge with the smallest cost between two consecutive vertices.
+// As in VisitEdges, edges with an infinite cost are skipped.
+func (C *CostView) PathEdges(path []int) ([]int, error) {
+	var ids []int
+	for i := 1; i < len(path); i++ {
+		best, bestw := -1, math.Inf(0)
+		C.A.VisitEdgeIDs(path[i-1], func(v int, _ float64, id int) {
+			if w := C.cost(C.A.Attrs(id)); v == path[i] && w < bestw {
+				best, bestw = id, w
+			}
+		})
+		if best == -1 {
+			return nil, &EdgeError{"PathEdges", path[i-1], path[i], ErrMissingEdge}
+		}
+		ids = append(ids, best)
+	}
+	return ids, nil
+}
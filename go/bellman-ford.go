@@ -8,64 +8,65 @@ It will only terminate incorrectly if there is a
 negative cycle, i.e., an cycle with all negative
 edge weights.
 */
-package main
+package shortestpath
 
-import (
-	"fmt"
-	"math"
-)
+// BellmanFord is the routine containing the setup and the algorithm
+// for finding the shortest path to ALL vertices from a given
+// starting point. G can be any Interface implementation, e.g. a Graph or a CSR graph.
+// If a negative-weight cycle can be reached from the start, the result is
+// meaningless, use BellmanFordCheck to detect this.
+func BellmanFord(G Interface, start int) ([]float64, []int) {
+	dist, prev, _ := BellmanFordCheckOf[float64](G, start)
+	return dist, prev
+}
 
-// a wrapper for the example
-func exampleBellmanFord(G *Graph, start, end int) {
-	// run the algorithm. It will yield all the shortest distances
-	// from the start node to all other vertices.
-	dist, prev := BellmanFord(G, start)
-	// recustruct the shortest path between the two points
-	fmt.Println("shortest path from vertex", start, "to vertex", end, ":")
-	if math.IsInf(dist[end], 0) {
-		err := fmt.Errorf("the selected vertex is not connected to the start point")
-		fmt.Println(err)
-	} else {
-		path := make([]int, 0, G.V)
-		// here we can use the same path reconstruction routine as for Dijkstra's algorithm
-		path, _ = getPathD(&start, end, prev, path)
-		fmt.Println(path)
+// BellmanFordOf is the same as BellmanFord for graphs with edge weights of
+// type W, e.g. BellmanFordOf[int64] for exact integer arithmetic.
+// The distance to unreachable vertices is Infinity[W]().
+func BellmanFordOf[W Number](G Weighted[W], start int) ([]W, []int) {
+	dist, prev, _ := BellmanFordCheckOf(G, start)
+	return dist, prev
+}
 
-		fmt.Println("with a total path length of", dist[end])
-	}
+// BellmanFordCheck is the same as BellmanFord, but returns ErrNegativeCycle
+// if a negative-weight cycle can be reached from the start
+func BellmanFordCheck(G Interface, start int) ([]float64, []int, error) {
+	return BellmanFordCheckOf[float64](G, start)
 }
 
-// BellmanFord is the routine containing the setup and the algorithm
-// for finding the shortest path to ALL vertices from a given
-// starting point.
-func BellmanFord(G *Graph, start int) ([]float64, []int) {
+// BellmanFordCheckOf is the same as BellmanFordCheck for graphs with edge weights of type W
+func BellmanFordCheckOf[W Number](G Weighted[W], start int) ([]W, []int, error) {
 	// Initialize the distances.
 	// I.e., this is the total distance from the source to any given point
-	dist := make([]float64, G.V)
+	V := G.Order()
+	dist := make([]W, V)
 	// Initialize the predecessors.
 	// I.e., this is the predecessor for any given point in the path from the source
-	prev := make([]int, G.V)
-	// Initialize explicit list of edges (obtained from adjacency matrix).
-	var edges [][]int
+	prev := make([]int, V)
+	// Initialize explicit list of edges (obtained from the graph).
+	// it has to be all edge combinations, i.e., both (u,v) and (v,u)
+	var edges []EdgeOf[W]
+	for e := range Arcs(G) {
+		edges = append(edges, e)
+	}
+	inf := Infinity[W]()
 	// Initialize data
-	for i := 0; i < G.V; i++ {
-		dist[i] = math.Inf(0)      // set distance to vertex i to "infinity"
-		prev[i] = -1               // set predecessor of vertex i to "undefined"
-		for j := 0; j < G.V; j++ { // it has to be all edge combinations, i.e., both (u,v) and (v,u)
-			if G.Nmat[i][j] == 1 {
-				edges = append(edges, []int{i, j})
-			}
-		}
+	for i := 0; i < V; i++ {
+		dist[i] = inf // set distance to vertex i to "infinity"
+		prev[i] = -1  // set predecessor of vertex i to "undefined"
 	}
-	dist[start] = 0.0   // set distance of the source vertex to 0
+	dist[start] = 0     // set distance of the source vertex to 0
 	prev[start] = start // set the predecessor of the source to itself
 
 	// repeated relaxation of edges (i => n-1 times)
-	for i := 1; i < G.V; i++ {
+	for i := 1; i < V; i++ {
 		for _, e := range edges {
-			u := e[0]
-			v := e[1]
-			newdist := dist[u] + float64(G.Emat[u][v])
+			u := e.From
+			v := e.To
+			if dist[u] == inf { // u has not been reached (yet)
+				continue
+			}
+			newdist := dist[u] + e.Weight
 			if newdist < dist[v] {
 				dist[v] = newdist
 				prev[v] = u
@@ -75,14 +76,16 @@ func BellmanFord(G *Graph, start int) ([]float64, []int) {
 
 	// check for negative cycles (would be the n-th iteration of the for-loop above)
 	for _, e := range edges {
-		u := e[0]
-		v := e[1]
-		newdist := dist[u] + float64(G.Emat[u][v])
+		u := e.From
+		v := e.To
+		if dist[u] == inf {
+			continue
+		}
+		newdist := dist[u] + e.Weight
 		if newdist < dist[v] {
-			err := fmt.Errorf("warning: the graph contains a negativ-weight cycle")
-			fmt.Println(err)
+			return dist, prev, ErrNegativeCycle
 		}
 	}
 
-	return dist, prev
+	return dist, prev, nil
 }
@@ -0,0 +1,156 @@
+// Command dimacs replays the queries of a DIMACS shortest-path challenge
+// instance against the routines of the shortestpath package, e.g.
+//
+//	dimacs -gr USA-road-d.NY.gr.gz -ss USA-road-d.NY.ss -algo dijkstra,fibonacci
+//
+// and prints the total and average time per query for each routine.
+// To avoid parsing large graphs again, they can be saved as a binary
+// snapshot with -save and then be loaded with -gr <file>.snap.
+package main
+
+import (
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	shortestpath "github.com/pprcht/shortestpaths/go"
+)
+
+// the routines that can be compared
+var solvers = map[string]shortestpath.Solver{
+	"dijkstra":    shortestpath.Dijkstra,
+	"fibonacci":   shortestpath.DijkstraFibonacci,
+	"bellmanford": shortestpath.BellmanFord,
+}
+
+func main() {
+	gr := flag.String("gr", "", "graph file (.gr, .gr.gz or a snapshot .snap)")
+	ss := flag.String("ss", "", "single-source query file (.ss)")
+	p2p := flag.String("p2p", "", "point-to-point query file (.p2p)")
+	save := flag.String("save", "", "save the graph as a binary snapshot")
+	algos := flag.String("algo", "dijkstra,fibonacci", "comma-separated routines: dijkstra, fibonacci, bellmanford")
+	flag.Parse()
+	if *gr == "" || (*ss == "") == (*p2p == "") {
+		fmt.Fprintln(os.Stderr, "usage: dimacs -gr <file> (-ss <file> | -p2p <file>) [-algo <list>]")
+		os.Exit(2)
+	}
+	if err := run(*gr, *ss+*p2p, *save, strings.Split(*algos, ",")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(grFile, queryFile, saveFile string, algos []string) error {
+	begin := time.Now()
+	var G *shortestpath.CSR
+	var err error
+	if strings.HasSuffix(grFile, ".snap") {
+		var S *shortestpath.Snapshot
+		if S, err = shortestpath.OpenSnapshot(grFile); err != nil {
+			return err
+		}
+		defer S.Close()
+		// a damaged snapshot would make the queries fail halfway
+		if err = S.Verify(); err != nil {
+			return fmt.Errorf("%s: %w", grFile, err)
+		}
+		G = S.CSR
+	} else {
+		err = readFile(grFile, func(r io.Reader) (err error) {
+			G, err = shortestpath.ReadDIMACS(r)
+			return err
+		})
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %d vertices, %d arcs, read in %v\n", grFile, G.V, G.E, time.Since(begin))
+
+	if saveFile != "" {
+		if err := saveSnapshot(saveFile, G); err != nil {
+			return err
+		}
+	}
+
+	var queries []shortestpath.Query
+	err = readFile(queryFile, func(r io.Reader) (err error) {
+		queries, err = shortestpath.ReadDIMACSQueries(r)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %d queries\n", queryFile, len(queries))
+
+	var first []float64 // results of the first routine, to compare the others
+	for _, name := range algos {
+		solve, ok := solvers[name]
+		if !ok {
+			return fmt.Errorf("unknown routine %q", name)
+		}
+		results, elapsed, err := shortestpath.ReplayQueries(G, queries, solve)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-12s total %v, %v per query\n", name, elapsed, elapsed/time.Duration(max(len(queries), 1)))
+		if first == nil {
+			first = results
+			continue
+		}
+		for i := range results {
+			if !sameResult(results[i], first[i]) {
+				fmt.Printf("%-12s result of query %d differs: %v instead of %v\n", name, i+1, results[i], first[i])
+			}
+		}
+	}
+	return nil
+}
+
+// are two results equal up to rounding? The routines add the
+// weights in a different order, so the sums can differ slightly.
+func sameResult(a, b float64) bool {
+	if a == b { // also for infinite results
+		return true
+	}
+	return math.Abs(a-b) <= 1e-9*max(math.Abs(a), math.Abs(b))
+}
+
+// write the graph as a binary snapshot
+func saveSnapshot(name string, G *shortestpath.CSR) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := shortestpath.WriteSnapshot(f, G, nil); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// open a (possibly gzipped) file and pass it to the reader
+func readFile(name string, read func(io.Reader) error) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(name, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	if err := read(r); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
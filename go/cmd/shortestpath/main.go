@@ -0,0 +1,158 @@
+// Command shortestpath is a small example program for the shortestpath package
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+
+	shortestpath "github.com/pprcht/shortestpaths/go"
+)
+
+func main() {
+	dot := flag.Bool("dot", false, "print the graph in Graphviz DOT format with the shortest path highlighted")
+	flag.Parse()
+
+	// set up a sample graph
+	G := shortestpath.NewGraph()
+
+	G.Example1() // all edges have the same weight
+	// three different paths from node 0 to 13 are possible
+
+	// G.Example2() // edges have different weights
+	//there is just one best path from 0 to 13
+
+	// start & end point
+	start := 0
+	end := 13
+
+	// or read the graph from a weight matrix file given as the first
+	// argument (optionally followed by the start and end points),
+	// the same file can be passed to the Fortran version
+	if flag.NArg() != 0 && flag.NArg() != 1 && flag.NArg() != 3 {
+		fmt.Fprintln(os.Stderr, "usage: shortestpath [-dot] [matrix file [start end]]")
+		os.Exit(2)
+	}
+	if flag.NArg() > 0 {
+		var err error
+		if G, err = readMatrix(flag.Arg(0)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		// by default from the first to the last vertex
+		start, end = 0, G.V-1
+		if flag.NArg() == 3 {
+			if start, err = strconv.Atoi(flag.Arg(1)); err != nil {
+				fmt.Fprintf(os.Stderr, "invalid start point %q\n", flag.Arg(1))
+				os.Exit(2)
+			}
+			if end, err = strconv.Atoi(flag.Arg(2)); err != nil {
+				fmt.Fprintf(os.Stderr, "invalid end point %q\n", flag.Arg(2))
+				os.Exit(2)
+			}
+		}
+	}
+	if start < 0 || start >= G.V || end < 0 || end >= G.V {
+		fmt.Fprintf(os.Stderr, "start and end point must be vertices of the graph (0 to %d)\n", G.V-1)
+		os.Exit(2)
+	}
+
+	// draw the graph, e.g. go run ./cmd/shortestpath -dot | dot -Tsvg > graph.svg
+	if *dot {
+		_, prev := shortestpath.Dijkstra(G, start)
+		path, _ := shortestpath.GetPathD(start, end, prev)
+		shortestpath.WriteDOT(os.Stdout, G, &shortestpath.DOTOptions{Path: path})
+		return
+	}
+
+	fmt.Println("Vertices", G.V)
+	fmt.Println("Edges", G.E)
+	fmt.Println()
+
+	//serach the shortest path using Dijkstra's algorithm
+	fmt.Println("Shortest path from", start, "to", end, "using Dijkstra's algorithm:")
+	exampleDijkstra(G, start, end)
+	fmt.Println()
+
+	//serach the shortest path using the Bellman-Ford algorithm
+	fmt.Println("Shortest path from", start, "to", end, "using the Bellman-Ford algorithm:")
+	exampleBellmanFord(G, start, end)
+	fmt.Println()
+
+	//serach the shortest path using the Floyd-Warshall algorithm
+	fmt.Println("Shortest path from", start, "to", end, "using the Floyd-Warshall algorithm:")
+	exampleFloydWarshall(G, start, end)
+	fmt.Println()
+
+	//serach the shortest path using Dijkstra's algorithm with a Fibonacci heap implementation
+	fmt.Println("Shortest path from", start, "to", end, "using Dijkstra's algorithm (Fibonacci heap):")
+	exampleDijkstraFibonacci(G, start, end)
+	fmt.Println()
+
+}
+
+// read a graph from a weight matrix file
+func readMatrix(name string) (*shortestpath.Graph, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return shortestpath.ReadMatrix(f)
+}
+
+// a wrapper for the Dijkstra example
+func exampleDijkstra(G *shortestpath.Graph, start, end int) {
+	// run the algorithm. It will yield all the shortest distances
+	// from the start node to all other vertices.
+	dist, prev := shortestpath.Dijkstra(G, start)
+	// recustruct the shortest path between the two points
+	printPath(start, end, dist, prev)
+}
+
+// a wrapper for the Bellman-Ford example
+func exampleBellmanFord(G *shortestpath.Graph, start, end int) {
+	dist, prev, err := shortestpath.BellmanFordCheck(G, start)
+	if err != nil {
+		fmt.Println("warning:", err)
+	}
+	// here we can use the same path reconstruction routine as for Dijkstra's algorithm
+	printPath(start, end, dist, prev)
+}
+
+// a wrapper for the Dijkstra (Fibonacci heap) example
+func exampleDijkstraFibonacci(G *shortestpath.Graph, start, end int) {
+	dist, prev := shortestpath.DijkstraFibonacci(G, start)
+	printPath(start, end, dist, prev)
+}
+
+// a wrapper for the Floyd-Warshall example
+func exampleFloydWarshall(G *shortestpath.Graph, start, end int) {
+	dist, prev := shortestpath.FloydWarshall(G)
+	fmt.Println("shortest path from vertex", start, "to vertex", end, ":")
+	if math.IsInf(dist[start][end], 0) {
+		err := fmt.Errorf("the selected vertex is not connected to the start point")
+		fmt.Println(err)
+	} else {
+		path := shortestpath.GetPathFW(prev, start, end)
+		fmt.Println(path)
+
+		fmt.Println("with a total path length of", dist[start][end])
+	}
+}
+
+// print the path from start to end for the single-source algorithms
+func printPath(start, end int, dist []float64, prev []int) {
+	fmt.Println("shortest path from vertex", start, "to vertex", end, ":")
+	if end >= len(prev) || math.IsInf(dist[end], 0) {
+		err := fmt.Errorf("the selected vertex is not connected to the start point")
+		fmt.Println(err)
+	} else {
+		path, _ := shortestpath.GetPathD(start, end, prev)
+		fmt.Println(path)
+
+		fmt.Println("with a total path length of", dist[end])
+	}
+}
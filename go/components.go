@@ -0,0 +1,165 @@
+/* This file contains routines to find out which vertices of
+a graph are connected to each other. For undirected graphs these
+are the connected components, for directed graphs the strongly
+connected components (found with Tarjan's algorithm), in which
+every vertex can be reached from every other vertex. */
+
+package shortestpath
+
+// Components returns the connected components of an undirected graph,
+// i.e., the component ID (0 to n-1) of each vertex and the number n of
+// components. For directed graphs these are the weakly connected components
+// (the direction of the edges is ignored), see StronglyConnected otherwise.
+// The IDs are numbered in the order of the smallest vertex in each component.
+func Components[W Number](G Weighted[W]) ([]int, int) {
+	V := G.Order()
+	// union-find with path halving
+	parent := make([]int, V)
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(v int) int {
+		for parent[v] != v {
+			parent[v] = parent[parent[v]]
+			v = parent[v]
+		}
+		return v
+	}
+	for e := range Arcs(G) {
+		a, b := find(e.From), find(e.To)
+		if a < b { // the smallest vertex is the root of each set
+			parent[b] = a
+		} else if b < a {
+			parent[a] = b
+		}
+	}
+
+	comp := make([]int, V)
+	n := 0
+	for v := 0; v < V; v++ {
+		if r := find(v); r == v {
+			comp[v] = n
+			n++
+		} else {
+			comp[v] = comp[r]
+		}
+	}
+	return comp, n
+}
+
+// StronglyConnected returns the strongly connected components of a
+// directed graph, i.e., the component ID (0 to n-1) of each vertex and
+// the number n of components. Two vertices are in the same component if
+// there are paths from the first to the second and back.
+// The components are found with (an iterative version of) Tarjan's
+// algorithm and are numbered in reverse topological order.
+func StronglyConnected[W Number](G Weighted[W]) ([]int, int) {
+	V := G.Order()
+	// collect the successors, as VisitEdges can't be paused
+	succ := make([][]int, V)
+	for u := 0; u < V; u++ {
+		G.VisitEdges(u, func(v int, w W) {
+			succ[u] = append(succ[u], v)
+		})
+	}
+
+	index := make([]int, V) // order of discovery (starting at 1, 0 = not visited)
+	low := make([]int, V)   // smallest index reachable from the vertex
+	comp := make([]int, V)
+	onStack := make([]bool, V)
+	var stack []int
+	type frame struct{ v, next int } // vertex and its next successor to visit
+	var calls []frame
+	counter, n := 0, 0
+
+	visit := func(v int) {
+		counter++
+		index[v], low[v] = counter, counter
+		stack = append(stack, v)
+		onStack[v] = true
+		calls = append(calls, frame{v, 0})
+	}
+	for s := 0; s < V; s++ {
+		if index[s] != 0 {
+			continue
+		}
+		visit(s)
+		for len(calls) > 0 {
+			f := &calls[len(calls)-1]
+			v := f.v
+			if f.next < len(succ[v]) {
+				w := succ[v][f.next]
+				f.next++
+				if index[w] == 0 {
+					visit(w)
+				} else if onStack[w] {
+					low[v] = min(low[v], index[w])
+				}
+				continue
+			}
+			// all successors of v are done, is v the root of a component?
+			if low[v] == index[v] {
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					comp[w] = n
+					if w == v {
+						break
+					}
+				}
+				n++
+			}
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				p := calls[len(calls)-1].v
+				low[p] = min(low[p], low[v])
+			}
+		}
+	}
+	return comp, n
+}
+
+// Largest returns the vertices of the largest component (in increasing
+// order), where comp are the component IDs returned by Components or
+// StronglyConnected. For components of equal size the smaller ID wins.
+func Largest(comp []int) []int {
+	size := make(map[int]int)
+	best := -1
+	for _, c := range comp {
+		size[c]++
+	}
+	for c, s := range size {
+		if best == -1 || s > size[best] || (s == size[best] && c < best) {
+			best = c
+		}
+	}
+	var vertices []int
+	for v, c := range comp {
+		if c == best {
+			vertices = append(vertices, v)
+		}
+	}
+	return vertices
+}
+
+// LargestComponent returns the largest connected component of the graph
+// (the largest strongly connected one for directed graphs) as a new graph,
+// e.g. to remove unreachable parts of imported data. The returned table
+// contains the new index of each old vertex (or -1 if it is removed).
+func (G *Graph) LargestComponent() (*Graph, []int) {
+	var comp []int
+	if G.directed {
+		comp, _ = StronglyConnected[float64](G)
+	} else {
+		comp, _ = Components[float64](G)
+	}
+	var vertices []int
+	for _, v := range Largest(comp) {
+		if !G.Deleted(v) {
+			vertices = append(vertices, v)
+		}
+	}
+	S, remap, _ := G.Induced(vertices)
+	return S, remap
+}
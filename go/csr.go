@@ -0,0 +1,211 @@
+/* This file contains a sparse graph representation in the
+compressed sparse row (CSR) format. Opposed to the adjacency
+matrices of Graph, the memory needed for a CSR graph is only
+proportional to the number of vertices plus the number of edges,
+which makes it suitable for large and sparse graphs (e.g. road networks). */
+
+package shortestpath
+
+import "sort"
+
+// EdgeOf is a single edge between two vertices with a weight of type W
+type EdgeOf[W Number] struct {
+	From   int // first vertex (source vertex for directed graphs)
+	To     int // second vertex (target vertex for directed graphs)
+	Weight W   // edge weight (edge length)
+}
+
+// Edge is a single (weighted) edge between two vertices
+type Edge = EdgeOf[float64]
+
+// CSROf is a graph with edge weights of type W
+// stored in compressed sparse row format.
+// The neighbours of vertex v are Targets[Offsets[v]:Offsets[v+1]]
+// and the corresponding edge weights are Weights[Offsets[v]:Offsets[v+1]].
+// For undirected graphs each edge is stored in both directions.
+type CSROf[W Number] struct {
+	V        int   // number of vertices (order of the graph)
+	E        int   // number of edges (size of the graph)
+	Offsets  []int // row offsets, length V+1
+	Targets  []int // neighbour (column) indices, sorted within each row
+	Weights  []W   // edge weights, same layout as Targets
+	directed bool  // is the graph a directed graph?
+}
+
+// CSR is a graph with float64 edge weights stored in compressed sparse row format
+type CSR = CSROf[float64]
+
+// NewCSR sets up a CSR graph with V vertices from a list of edges.
+// Edges with vertices outside the order of the graph are skipped
+// and, like for Graph.AddEdge, parallel edges are avoided
+// (only the first occurrence of an edge is kept).
+func NewCSR(V int, directed bool, edges []Edge) *CSR {
+	C, _ := NewCSRParallel(V, directed, edges, KeepFirst)
+	return C
+}
+
+// NewCSROf is the same as NewCSR for edge weights of type W,
+// e.g. NewCSROf[int64] for a graph with exact integer weights.
+func NewCSROf[W Number](V int, directed bool, edges []EdgeOf[W]) *CSROf[W] {
+	C, _ := NewCSRParallel(V, directed, edges, KeepFirst)
+	return C
+}
+
+// NewCSRParallel sets up a CSR graph with V vertices from a list
+// of edges like NewCSR, but parallel edges are merged according to
+// the policy p. For RejectParallel an *EdgeError is returned for the
+// first parallel edge that is found.
+func NewCSRParallel[W Number](V int, directed bool, edges []EdgeOf[W], p Parallel) (*CSROf[W], error) {
+	C := new(CSROf[W])
+	C.V = V
+	C.directed = directed
+
+	// count the number of entries per row
+	count := make([]int, V+1)
+	for _, e := range edges {
+		if !C.valid(e) {
+			continue
+		}
+		count[e.From+1]++
+		if !directed && e.From != e.To {
+			count[e.To+1]++
+		}
+	}
+	for i := 0; i < V; i++ {
+		count[i+1] += count[i]
+	}
+
+	// fill the rows, keeping the input order of the edges
+	targets := make([]int, count[V])
+	weights := make([]W, count[V])
+	order := make([]int, count[V]) // position of the edge in the input
+	next := make([]int, V)
+	copy(next, count[:V])
+	for k, e := range edges {
+		if !C.valid(e) {
+			continue
+		}
+		targets[next[e.From]], weights[next[e.From]], order[next[e.From]] = e.To, e.Weight, k
+		next[e.From]++
+		if !directed && e.From != e.To {
+			targets[next[e.To]], weights[next[e.To]], order[next[e.To]] = e.From, e.Weight, k
+			next[e.To]++
+		}
+	}
+
+	// sort each row by target and drop parallel edges
+	C.Offsets = make([]int, V+1)
+	n := 0
+	for v := 0; v < V; v++ {
+		row := csrRow[W]{targets[count[v]:count[v+1]], weights[count[v]:count[v+1]], order[count[v]:count[v+1]]}
+		sort.Sort(row)
+		for i := range row.targets {
+			if i > 0 && row.targets[i] == row.targets[i-1] {
+				if p == RejectParallel {
+					e := edges[row.order[i]]
+					return nil, &EdgeError{"NewCSR", e.From, e.To, ErrDuplicateEdge}
+				}
+				weights[n-1] = merge(p, weights[n-1], row.weights[i])
+				continue
+			}
+			targets[n] = row.targets[i]
+			weights[n] = row.weights[i]
+			n++
+		}
+		C.Offsets[v+1] = n
+	}
+	C.Targets = targets[:n:n]
+	C.Weights = weights[:n:n]
+
+	// count the edges (undirected edges are stored twice, loops only once)
+	if directed {
+		C.E = n
+	} else {
+		for v := 0; v < V; v++ {
+			for _, w := range C.Targets[C.Offsets[v]:C.Offsets[v+1]] {
+				if w >= v {
+					C.E++
+				}
+			}
+		}
+	}
+	return C, nil
+}
+
+// CSRFromGraph converts a Graph (adjacency matrices) into the CSR format
+func CSRFromGraph(G *Graph) *CSR {
+	var edges []Edge
+	for e := range G.Edges() {
+		edges = append(edges, e)
+	}
+	return NewCSR(G.V, G.directed, edges)
+}
+
+// Directed reports whether C is a directed graph
+func (C *CSROf[W]) Directed() bool {
+	return C.directed
+}
+
+// is the edge within the order of the graph?
+func (C *CSROf[W]) valid(e EdgeOf[W]) bool {
+	return e.From >= 0 && e.From < C.V && e.To >= 0 && e.To < C.V
+}
+
+// Neighbours returns the neighbours of a vertex together with the
+// weights of the corresponding edges. The slices must not be modified.
+func (C *CSROf[W]) Neighbours(v int) ([]int, []W) {
+	return C.Targets[C.Offsets[v]:C.Offsets[v+1]], C.Weights[C.Offsets[v]:C.Offsets[v+1]]
+}
+
+// Degree returns the number of neighbours of a vertex
+func (C *CSROf[W]) Degree(v int) int {
+	return C.Offsets[v+1] - C.Offsets[v]
+}
+
+// HasEdge runs a check on a graph: is a given edge part of the graph?
+func (C *CSROf[W]) HasEdge(v1, v2 int) bool {
+	row := C.Targets[C.Offsets[v1]:C.Offsets[v1+1]]
+	i := sort.SearchInts(row, v2)
+	return i < len(row) && row[i] == v2
+}
+
+// Weight returns the weight (length) of an edge (0 if there is no such edge)
+func (C *CSROf[W]) Weight(v1, v2 int) W {
+	row := C.Targets[C.Offsets[v1]:C.Offsets[v1+1]]
+	i := sort.SearchInts(row, v2)
+	if i < len(row) && row[i] == v2 {
+		return C.Weights[C.Offsets[v1]+i]
+	}
+	return 0
+}
+
+// Order returns the number of vertices (order of the graph)
+func (C *CSROf[W]) Order() int { return C.V }
+
+// VisitEdges calls fn for every neighbour v of vertex u
+// together with the weight of the edge (u,v)
+func (C *CSROf[W]) VisitEdges(u int, fn func(v int, w W)) {
+	for k := C.Offsets[u]; k < C.Offsets[u+1]; k++ {
+		fn(C.Targets[k], C.Weights[k])
+	}
+}
+
+// csrRow is used to sort a single row of a CSR graph by target vertex
+type csrRow[W Number] struct {
+	targets []int
+	weights []W
+	order   []int
+}
+
+func (r csrRow[W]) Len() int { return len(r.targets) }
+func (r csrRow[W]) Less(i, j int) bool {
+	if r.targets[i] != r.targets[j] {
+		return r.targets[i] < r.targets[j]
+	}
+	return r.order[i] < r.order[j]
+}
+func (r csrRow[W]) Swap(i, j int) {
+	r.targets[i], r.targets[j] = r.targets[j], r.targets[i]
+	r.weights[i], r.weights[j] = r.weights[j], r.weights[i]
+	r.order[i], r.order[j] = r.order[j], r.order[i]
+}
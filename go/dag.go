@@ -0,0 +1,143 @@
+/* This file contains routines for directed acyclic graphs (DAGs),
+e.g. build pipelines or schedules. On a DAG the shortest (and longest)
+paths can be found in linear time by relaxing the edges of the vertices
+in topological order, also for negative edge weights. */
+
+package shortestpath
+
+// TopologicalSort returns the vertices of a directed graph in topological
+// order, i.e., for every edge (u,v) u comes before v (Kahn's algorithm).
+// If the graph contains a cycle ErrCycle is returned.
+func TopologicalSort[W Number](G Weighted[W]) ([]int, error) {
+	V := G.Order()
+	indeg := make([]int, V) // number of edges entering each vertex
+	for e := range Arcs(G) {
+		indeg[e.To]++
+	}
+	order := make([]int, 0, V)
+	for v := 0; v < V; v++ {
+		if indeg[v] == 0 {
+			order = append(order, v)
+		}
+	}
+	// order is used as the queue of vertices without remaining incoming edges
+	for i := 0; i < len(order); i++ {
+		G.VisitEdges(order[i], func(v int, w W) {
+			indeg[v]--
+			if indeg[v] == 0 {
+				order = append(order, v)
+			}
+		})
+	}
+	if len(order) < V {
+		return nil, ErrCycle
+	}
+	return order, nil
+}
+
+// FindCycle returns the vertices of a cycle in a directed graph
+// (the last vertex has an edge to the first one),
+// or nil if the graph is acyclic.
+func FindCycle[W Number](G Weighted[W]) []int {
+	V := G.Order()
+	succ := make([][]int, V)
+	for u := 0; u < V; u++ {
+		G.VisitEdges(u, func(v int, w W) {
+			succ[u] = append(succ[u], v)
+		})
+	}
+	const (
+		white = iota // not visited
+		grey         // on the current search path
+		black        // done
+	)
+	color := make([]int, V)
+	parent := make([]int, V)
+	next := make([]int, V) // next successor to visit
+	for s := 0; s < V; s++ {
+		if color[s] != white {
+			continue
+		}
+		color[s] = grey
+		parent[s] = -1
+		v := s
+		for v != -1 {
+			if next[v] == len(succ[v]) {
+				color[v] = black
+				v = parent[v]
+				continue
+			}
+			w := succ[v][next[v]]
+			next[v]++
+			switch color[w] {
+			case white:
+				color[w] = grey
+				parent[w] = v
+				v = w
+			case grey: // found the cycle w -> ... -> v -> w
+				cycle := []int{v}
+				for u := v; u != w; {
+					u = parent[u]
+					cycle = append(cycle, u)
+				}
+				for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+					cycle[i], cycle[j] = cycle[j], cycle[i]
+				}
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// DAGShortest finds the shortest paths from a starting point to ALL
+// vertices of a directed acyclic graph in O(V+E), negative edge weights
+// are allowed. It returns the distances and predecessors like Dijkstra
+// (unreachable vertices have the distance Infinity[W]()), or ErrCycle.
+func DAGShortest[W Number](G Weighted[W], start int) ([]W, []int, error) {
+	return dagPaths(G, start, false)
+}
+
+// DAGLongest finds the longest paths (e.g. the critical path of a schedule)
+// from a starting point to ALL vertices of a directed acyclic graph in O(V+E).
+// It returns the distances and predecessors like Dijkstra
+// (unreachable vertices have the distance -Infinity[W]()), or ErrCycle.
+func DAGLongest[W Number](G Weighted[W], start int) ([]W, []int, error) {
+	return dagPaths(G, start, true)
+}
+
+// relax the edges in topological order, keeping the shortest
+// or longest distance to each vertex
+func dagPaths[W Number](G Weighted[W], start int, longest bool) ([]W, []int, error) {
+	order, err := TopologicalSort(G)
+	if err != nil {
+		return nil, nil, err
+	}
+	V := G.Order()
+	inf := Infinity[W]()
+	if longest {
+		inf = -inf
+	}
+	dist := make([]W, V)
+	prev := make([]int, V)
+	for i := 0; i < V; i++ {
+		dist[i] = inf // set distance to vertex i to "infinity"
+		prev[i] = -1  // set predecessor of vertex i to "undefined"
+	}
+	dist[start] = 0
+	prev[start] = start
+
+	for _, u := range order {
+		if dist[u] == inf { // u can't be reached from the start
+			continue
+		}
+		G.VisitEdges(u, func(v int, w W) {
+			newdist := dist[u] + w
+			if (!longest && newdist < dist[v]) || (longest && newdist > dist[v]) {
+				dist[v] = newdist
+				prev[v] = u
+			}
+		})
+	}
+	return dist, prev, nil
+}
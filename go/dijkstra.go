@@ -2,53 +2,39 @@
 This file contains routines to run a shortest-path
 search using Dijkstra's algorithm.
 */
-package main
+package shortestpath
 
 import (
 	"fmt"
-	"math"
 )
 
-// a wrapper for the example
-func exampleDijkstra(G *Graph, start, end int) {
-	// run the algorithm. It will yield all the shortest distances
-	// from the start node to all other vertices.
-	dist, prev := Dijkstra(G, start)
-	// recustruct the shortest path between the two points
-	fmt.Println("shortest path from vertex", start, "to vertex", end, ":")
-	if end >= len(prev) || math.IsInf(dist[end], 0) {
-		err := fmt.Errorf("the selected vertex is not connected to the start point")
-		fmt.Println(err)
-	} else {
-		path := make([]int, 0, G.V)
-		path, _ = getPathD(&start, end, prev, path)
-		fmt.Println(path)
-
-		fmt.Println("with a total path length of", dist[end])
-	}
-}
-
 // Dijkstra is the routine containing the setup and the algorithm
 // for finding the shortest path to ALL vertices from a given
-// starting point.
-func Dijkstra(G *Graph, start int) ([]float64, []int) {
+// starting point. G can be any Interface implementation, e.g. a Graph or a CSR graph.
+func Dijkstra(G Interface, start int) ([]float64, []int) {
+	return DijkstraOf[float64](G, start)
+}
+
+// DijkstraOf is the same as Dijkstra for graphs with edge weights of
+// type W, e.g. DijkstraOf[int64] for exact integer arithmetic.
+// The distance to unreachable vertices is Infinity[W]().
+func DijkstraOf[W Number](G Weighted[W], start int) ([]W, []int) {
 	// Initialize the distances.
 	// I.e., this is the total distance from the source to any given point
-	dist := make([]float64, G.V)
+	V := G.Order()
+	dist := make([]W, V)
 	// Initialize the predecessors.
 	// I.e., this is the predecessor for any given point in the path from the source
-	prev := make([]int, G.V)
+	prev := make([]int, V)
 	// Initialize the list of unvisited vertices.
-	Q := make([]int, G.V)
-	// Initialize neighbour lists for all vertices (for easy access later).
-	neigh := G.Nlist() // get the neighbour lists for easy access
+	Q := make([]int, V)
 	// Initialize data
-	for i := 0; i < G.V; i++ {
-		dist[i] = math.Inf(0) // set distance to vertex i to "infinity"
-		prev[i] = -1          // set predecessor of vertex i to "undefined"
-		Q[i] = i              // add the vertex to the queue
+	for i := 0; i < V; i++ {
+		dist[i] = Infinity[W]() // set distance to vertex i to "infinity"
+		prev[i] = -1            // set predecessor of vertex i to "undefined"
+		Q[i] = i                // add the vertex to the queue
 	}
-	dist[start] = 0.0   // set distance of the source vertex to 0
+	dist[start] = 0     // set distance of the source vertex to 0
 	prev[start] = start // set the predecessor of the source to itself
 
 	// As long as there are unvisited vertices in Q, we continue
@@ -62,10 +48,10 @@ func Dijkstra(G *Graph, start int) ([]float64, []int) {
 
 		if err == nil {
 			// loop over all neighbours of u
-			for _, v := range neigh[u] {
+			G.VisitEdges(u, func(v int, w W) {
 				// except vertex v to avoid walking back
 				if prev[u] != v {
-					newdist := dist[u] + float64(G.getWeight(u, v))
+					newdist := dist[u] + w
 					// is the disteance from u to v smaller than the old distance saved for v?
 					if newdist < dist[v] {
 						// if so, update distance and predecessors for v
@@ -73,7 +59,7 @@ func Dijkstra(G *Graph, start int) ([]float64, []int) {
 						prev[v] = u
 					}
 				}
-			}
+			})
 		}
 		// if we are only interested in the path between the start and end nodes
 		// we could already exit the loop after we have "visited" the end vertex
@@ -83,11 +69,12 @@ func Dijkstra(G *Graph, start int) ([]float64, []int) {
 }
 
 // minQ gets the vertex with the smallest distance to the source from Q
-func minQ(Q []int, dist []float64) (int, int, error) {
+func minQ[W Number](Q []int, dist []W) (int, int, error) {
 	var vertex int
 	var pos int
 	var err error
-	dref := math.Inf(0)
+	inf := Infinity[W]()
+	dref := inf
 	for i, k := range Q {
 		d := dist[k]
 		if d < dref {
@@ -96,16 +83,26 @@ func minQ(Q []int, dist []float64) (int, int, error) {
 			dref = d
 		}
 	}
-	if math.IsInf(dref, 0) {
+	if dref == inf {
 		err = fmt.Errorf("Warning: disconnected graph")
 	}
 	return vertex, pos, err
 }
 
+// GetPathD analyzes the predecessors prev returned by Dijkstra, DijkstraFibonacci
+// or BellmanFord and constructs the shortest possible path from start to end.
+func GetPathD(start, end int, prev []int) ([]int, error) {
+	path := make([]int, 0, len(prev))
+	return getPathD(&start, end, prev, path)
+}
+
 // analyze the predecessors (recursion) and construct the shortest possible path
 func getPathD(start *int, pos int, prev []int, path []int) ([]int, error) {
 	var err error
 	if pos != *start {
+		if prev[pos] == -1 {
+			return nil, fmt.Errorf("vertex %d is not connected to the start point", pos)
+		}
 		path = append([]int{pos}, path...) //prepend slice
 		path, err = getPathD(start, prev[pos], prev, path)
 	} else {
@@ -0,0 +1,270 @@
+/* This file contains readers for the file formats of the 9th DIMACS
+implementation challenge on shortest paths, in which the standard
+benchmark instances (e.g. the road networks of the USA) are distributed:
+
+	.gr   the graph:       p sp <n> <m>,        a <u> <v> <w>
+	.co   the coordinates: p aux sp co <n>,     v <id> <x> <y>
+	.ss   sources:         p aux sp ss <k>,     s <u>
+	.p2p  point-to-point:  p aux sp p2p <k>,    q <u> <v>
+
+Lines starting with c are comments. The vertices are numbered from 1
+in the files and from 0 in the graphs returned here.
+See http://www.diag.uniroma1.it/challenge9/format.shtml */
+
+package shortestpath
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Point holds the coordinates of a vertex
+type Point struct {
+	X, Y float64
+}
+
+// Query is a shortest-path query from a source to a target,
+// the target is -1 for single-source queries
+type Query struct {
+	Source, Target int
+}
+
+// Solver is a single-source shortest-path routine like Dijkstra,
+// DijkstraFibonacci or BellmanFord
+type Solver func(G Interface, start int) ([]float64, []int)
+
+// ReadDIMACS reads a directed graph from a DIMACS .gr file.
+// Of several parallel arcs only the shortest one is kept.
+func ReadDIMACS(r io.Reader) (*CSR, error) {
+	V, m := -1, 0
+	var edges []Edge
+	err := scanDIMACS(r, func(fields []string) error {
+		switch fields[0] {
+		case "p":
+			if V != -1 || len(fields) != 4 || fields[1] != "sp" {
+				return errors.New("invalid problem line, want \"p sp <n> <m>\"")
+			}
+			var err error
+			if V, err = parseCount(fields[2]); err != nil {
+				return err
+			}
+			if m, err = parseCount(fields[3]); err != nil {
+				return err
+			}
+			return checkOrder(V, MaxCSROrder)
+		case "a":
+			if V == -1 {
+				return errors.New("arc before the problem line")
+			}
+			if len(fields) != 4 {
+				return fmt.Errorf("expected 4 fields, got %d", len(fields))
+			}
+			u, err := parseDIMACSVertex(fields[1], V)
+			if err != nil {
+				return err
+			}
+			v, err := parseDIMACSVertex(fields[2], V)
+			if err != nil {
+				return err
+			}
+			w, err := strconv.ParseFloat(fields[3], 64)
+			if err != nil || math.IsNaN(w) || math.IsInf(w, 0) {
+				return fmt.Errorf("invalid weight %q: %w", fields[3], ErrWeight)
+			}
+			edges = append(edges, Edge{u, v, w})
+		default:
+			return fmt.Errorf("unknown line type %q", fields[0])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if V == -1 {
+		return nil, errors.New("missing problem line")
+	}
+	if len(edges) != m {
+		return nil, fmt.Errorf("expected %d arcs, got %d", m, len(edges))
+	}
+	return NewCSRParallel(V, true, edges, KeepMin)
+}
+
+// ReadDIMACSCoordinates reads the coordinates of the vertices
+// from a DIMACS .co file
+func ReadDIMACSCoordinates(r io.Reader) ([]Point, error) {
+	var points []Point
+	V := -1
+	err := scanDIMACS(r, func(fields []string) error {
+		switch fields[0] {
+		case "p":
+			if V != -1 || len(fields) != 5 || fields[1] != "aux" || fields[2] != "sp" || fields[3] != "co" {
+				return errors.New("invalid problem line, want \"p aux sp co <n>\"")
+			}
+			var err error
+			if V, err = parseCount(fields[4]); err != nil {
+				return err
+			}
+			if err := checkOrder(V, MaxCSROrder); err != nil {
+				return err
+			}
+			points = make([]Point, V)
+		case "v":
+			if V == -1 {
+				return errors.New("vertex before the problem line")
+			}
+			if len(fields) != 4 {
+				return fmt.Errorf("expected 4 fields, got %d", len(fields))
+			}
+			v, err := parseDIMACSVertex(fields[1], V)
+			if err != nil {
+				return err
+			}
+			if points[v].X, err = strconv.ParseFloat(fields[2], 64); err != nil {
+				return fmt.Errorf("invalid coordinate %q", fields[2])
+			}
+			if points[v].Y, err = strconv.ParseFloat(fields[3], 64); err != nil {
+				return fmt.Errorf("invalid coordinate %q", fields[3])
+			}
+		default:
+			return fmt.Errorf("unknown line type %q", fields[0])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if V == -1 {
+		return nil, errors.New("missing problem line")
+	}
+	return points, nil
+}
+
+// ReadDIMACSQueries reads the queries from a DIMACS .ss
+// (single-source) or .p2p (point-to-point) file
+func ReadDIMACSQueries(r io.Reader) ([]Query, error) {
+	var queries []Query
+	kind := ""
+	k := 0
+	err := scanDIMACS(r, func(fields []string) error {
+		switch fields[0] {
+		case "p":
+			if kind != "" || len(fields) != 5 || fields[1] != "aux" || fields[2] != "sp" || (fields[3] != "ss" && fields[3] != "p2p") {
+				return errors.New("invalid problem line, want \"p aux sp ss|p2p <k>\"")
+			}
+			kind = fields[3]
+			var err error
+			if k, err = parseCount(fields[4]); err != nil {
+				return err
+			}
+		case "s", "q":
+			if (fields[0] == "s" && kind != "ss") || (fields[0] == "q" && kind != "p2p") {
+				return fmt.Errorf("unexpected line type %q", fields[0])
+			}
+			want := 2
+			if kind == "p2p" {
+				want = 3
+			}
+			if len(fields) != want {
+				return fmt.Errorf("expected %d fields, got %d", want, len(fields))
+			}
+			q := Query{Target: -1}
+			var err error
+			if q.Source, err = parseDIMACSVertex(fields[1], math.MaxInt); err != nil {
+				return err
+			}
+			if kind == "p2p" {
+				if q.Target, err = parseDIMACSVertex(fields[2], math.MaxInt); err != nil {
+					return err
+				}
+			}
+			queries = append(queries, q)
+		default:
+			return fmt.Errorf("unknown line type %q", fields[0])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if kind == "" {
+		return nil, errors.New("missing problem line")
+	}
+	if len(queries) != k {
+		return nil, fmt.Errorf("expected %d queries, got %d", k, len(queries))
+	}
+	return queries, nil
+}
+
+// ReplayQueries runs the queries (e.g. from a DIMACS .ss or .p2p file)
+// on the graph with the given routine and measures the total time.
+// For each query the result is the distance to the target, or the sum
+// of the distances to all reachable vertices for single-source queries,
+// so that the results of different routines can be compared.
+func ReplayQueries(G Interface, queries []Query, solve Solver) ([]float64, time.Duration, error) {
+	results := make([]float64, len(queries))
+	for _, q := range queries {
+		if q.Source >= G.Order() || q.Target >= G.Order() {
+			return nil, 0, &EdgeError{"ReplayQueries", q.Source, q.Target, ErrVertexRange}
+		}
+	}
+	begin := time.Now()
+	for i, q := range queries {
+		dist, _ := solve(G, q.Source)
+		if q.Target != -1 {
+			results[i] = dist[q.Target]
+			continue
+		}
+		for _, d := range dist {
+			if !math.IsInf(d, 0) {
+				results[i] += d
+			}
+		}
+	}
+	return results, time.Since(begin), nil
+}
+
+// call fn with the fields of each line, skipping comments and empty lines
+func scanDIMACS(r io.Reader, fn func(fields []string) error) error {
+	sc := bufio.NewScanner(r)
+	n := 0
+	for sc.Scan() {
+		n++
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || fields[0] == "c" {
+			continue
+		}
+		if err := fn(fields); err != nil {
+			return &ParseError{n, err}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return &ParseError{n + 1, err}
+	}
+	return nil
+}
+
+func parseCount(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid count %q", s)
+	}
+	return n, nil
+}
+
+// parse a vertex numbered from 1 to V and return it numbered from 0
+func parseDIMACSVertex(s string, V int) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid vertex %q", s)
+	}
+	if v < 1 || v > V {
+		return 0, fmt.Errorf("vertex %d: %w", v, ErrVertexRange)
+	}
+	return v - 1, nil
+}
@@ -0,0 +1,113 @@
+/* This file contains routines to export a graph in the DOT language
+of Graphviz, e.g. to draw the example graphs with
+
+	dot -Tsvg graph.dot > graph.svg
+
+The edge weights are shown as edge labels, and a shortest path
+or a whole shortest-path tree can be highlighted. */
+
+package shortestpath
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DOTOptions selects what is highlighted in the output of WriteDOT
+type DOTOptions struct {
+	Name  string // name of the graph ("G" if empty)
+	Path  []int  // vertices of a path to highlight, e.g. from GetPathD or GetPathFW
+	Tree  []int  // predecessors of a shortest-path tree to highlight, e.g. from Dijkstra
+	Color string // color of the highlighted vertices and edges ("red" if empty)
+}
+
+// WriteDOT writes a graph in the DOT language of Graphviz, with the
+// weights as edge labels. The labels of the vertices of a Graph are used
+// as vertex labels. Use nil options for no highlighting.
+func WriteDOT(w io.Writer, G EdgeLister, opt *DOTOptions) error {
+	if opt == nil {
+		opt = new(DOTOptions)
+	}
+	name, color := opt.Name, opt.Color
+	if name == "" {
+		name = "G"
+	}
+	if color == "" {
+		color = "red"
+	}
+	kind, arrow := "graph", "--"
+	if G.Directed() {
+		kind, arrow = "digraph", "->"
+	}
+
+	// the highlighted vertices and edges
+	vertices := make(map[int]bool)
+	edges := make(map[[2]int]bool)
+	mark := func(u, v int) {
+		if !G.Directed() && u > v {
+			u, v = v, u
+		}
+		edges[[2]int{u, v}] = true
+		vertices[u], vertices[v] = true, true
+	}
+	for i := 1; i < len(opt.Path); i++ {
+		mark(opt.Path[i-1], opt.Path[i])
+	}
+	if len(opt.Path) == 1 {
+		vertices[opt.Path[0]] = true
+	}
+	for v, u := range opt.Tree {
+		if u == v {
+			vertices[v] = true // the root
+		} else if u != -1 {
+			mark(u, v)
+		}
+	}
+
+	labels, _ := G.(interface{ Label(v int) string })
+	deleted, _ := G.(interface{ Deleted(v int) bool })
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s %s {\n", kind, dotQuote(name))
+	for v := 0; v < G.Order(); v++ {
+		if deleted != nil && deleted.Deleted(v) {
+			continue
+		}
+		var attrs []string
+		if labels != nil {
+			if l := labels.Label(v); l != "" {
+				attrs = append(attrs, "label="+dotQuote(l))
+			}
+		}
+		if vertices[v] {
+			attrs = append(attrs, "color="+dotQuote(color))
+		}
+		writeDOTLine(bw, strconv.Itoa(v), attrs)
+	}
+	for e := range G.Edges() {
+		attrs := []string{"label=" + dotQuote(formatFloat(e.Weight))}
+		if edges[[2]int{e.From, e.To}] {
+			attrs = append(attrs, "color="+dotQuote(color), "penwidth=2")
+		}
+		writeDOTLine(bw, fmt.Sprintf("%d %s %d", e.From, arrow, e.To), attrs)
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// write a vertex or edge statement with its attributes
+func writeDOTLine(w io.Writer, stmt string, attrs []string) {
+	if len(attrs) == 0 {
+		fmt.Fprintf(w, "\t%s;\n", stmt)
+	} else {
+		fmt.Fprintf(w, "\t%s [%s];\n", stmt, strings.Join(attrs, ", "))
+	}
+}
+
+// quote a DOT string
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
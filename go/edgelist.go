@@ -0,0 +1,217 @@
+/* This file contains routines to read and write graphs as a plain-text
+edge list, one edge per line. A simple example looks like this:
+
+	# lines starting with # are comments
+	16 undirected
+	0 1 1.85
+	0,4,1.36
+
+The optional header line contains the number of vertices and
+optionally "directed" or "undirected" (the default). Without a header
+the number of vertices is given by the largest vertex index.
+Each edge line contains two vertex indices and optionally a weight
+(1.0 if missing), separated by whitespace or commas. */
+
+package shortestpath
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseError is returned when a graph file can't be read.
+// It contains the number of the line (starting at 1) with the error.
+type ParseError struct {
+	Line int   // line number
+	Err  error // the reason
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the reason of the error
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Limits for the number of vertices of graph files, which protect the readers
+// against headers that would exhaust the memory. They can be raised if needed.
+var (
+	MaxOrder    = 1 << 12 // for a Graph, whose matrices need 16 bytes per vertex pair (256 MiB)
+	MaxCSROrder = 1 << 27 // for a CSR graph (the largest DIMACS road network has 24M vertices)
+)
+
+// checkOrder returns an error if V exceeds the limit (or has overflowed)
+func checkOrder(V, limit int) error {
+	if V < 0 || V > limit {
+		return fmt.Errorf("%d vertices, the limit is %d: %w", V, limit, ErrOrder)
+	}
+	return nil
+}
+
+// EdgeLister is a graph that can list all its edges,
+// it is implemented by Graph and CSR
+type EdgeLister interface {
+	Order() int
+	Directed() bool
+	Edges() iter.Seq[Edge]
+}
+
+// edgeList is the content of an edge list file
+type edgeList struct {
+	V        int    // number of vertices
+	directed bool   // directed graph?
+	edges    []Edge // all edges
+	lines    []int  // line number of each edge
+	header   int    // line number of the header (or of the edge with the largest vertex)
+}
+
+// ReadEdgeList reads a graph from an edge list
+func ReadEdgeList(r io.Reader) (*Graph, error) {
+	l, err := parseEdgeList(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOrder(l.V, MaxOrder); err != nil {
+		return nil, &ParseError{l.header, err}
+	}
+	G := NewGraph()
+	G.directed = l.directed
+	G.SetOrder(l.V)
+	for i, e := range l.edges {
+		if err := G.AddEdge(e.From, e.To, e.Weight); err != nil {
+			return nil, &ParseError{l.lines[i], err}
+		}
+	}
+	return G, nil
+}
+
+// ReadEdgeListCSR reads a graph from an edge list into the CSR format.
+// Like for NewCSR only the first of several parallel edges is kept,
+// self-loops are rejected as by ReadEdgeList.
+func ReadEdgeListCSR(r io.Reader) (*CSR, error) {
+	l, err := parseEdgeList(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOrder(l.V, MaxCSROrder); err != nil {
+		return nil, &ParseError{l.header, err}
+	}
+	for i, e := range l.edges {
+		if e.From >= l.V || e.To >= l.V {
+			return nil, &ParseError{l.lines[i], &EdgeError{"ReadEdgeList", e.From, e.To, ErrVertexRange}}
+		}
+		if e.From == e.To {
+			return nil, &ParseError{l.lines[i], &EdgeError{"ReadEdgeList", e.From, e.To, ErrSelfLoop}}
+		}
+	}
+	return NewCSR(l.V, l.directed, l.edges), nil
+}
+
+// parse the lines of an edge list
+func parseEdgeList(r io.Reader) (*edgeList, error) {
+	l := new(edgeList)
+	l.V = -1
+	maxV := -1
+	sc := bufio.NewScanner(r)
+	n := 0
+	for sc.Scan() {
+		n++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		fields := strings.FieldsFunc(line, func(c rune) bool {
+			return c == ',' || c == ' ' || c == '\t'
+		})
+
+		// the header: number of vertices and directedness
+		if l.V == -1 && len(l.edges) == 0 && (len(fields) == 1 || isDirectedness(fields[1])) {
+			V, err := strconv.Atoi(fields[0])
+			if err != nil || V < 0 {
+				return nil, &ParseError{n, fmt.Errorf("invalid number of vertices %q", fields[0])}
+			}
+			l.V = V
+			l.header = n
+			if len(fields) > 1 {
+				l.directed = fields[1] == "directed"
+			}
+			if len(fields) > 2 {
+				return nil, &ParseError{n, errors.New("too many fields in the header")}
+			}
+			continue
+		}
+
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, &ParseError{n, fmt.Errorf("expected 2 or 3 fields, got %d", len(fields))}
+		}
+		e := Edge{Weight: 1.0}
+		var err error
+		if e.From, err = parseVertex(fields[0]); err != nil {
+			return nil, &ParseError{n, err}
+		}
+		if e.To, err = parseVertex(fields[1]); err != nil {
+			return nil, &ParseError{n, err}
+		}
+		if len(fields) == 3 {
+			if e.Weight, err = strconv.ParseFloat(fields[2], 64); err != nil || math.IsNaN(e.Weight) || math.IsInf(e.Weight, 0) {
+				return nil, &ParseError{n, fmt.Errorf("invalid weight %q: %w", fields[2], ErrWeight)}
+			}
+		}
+		if e.From > maxV || e.To > maxV {
+			maxV = max(e.From, e.To)
+			if l.V == -1 {
+				l.header = n
+			}
+		}
+		l.edges = append(l.edges, e)
+		l.lines = append(l.lines, n)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, &ParseError{n + 1, err}
+	}
+	if l.V == -1 {
+		l.V = maxV + 1
+	}
+	return l, nil
+}
+
+func isDirectedness(s string) bool {
+	return s == "directed" || s == "undirected"
+}
+
+func parseVertex(s string) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid vertex %q", s)
+	}
+	if v >= MaxCSROrder {
+		return 0, fmt.Errorf("vertex %d, the limit is %d: %w", v, MaxCSROrder, ErrOrder)
+	}
+	return v, nil
+}
+
+// WriteEdgeList writes a graph as an edge list (with header),
+// which can be read again with ReadEdgeList
+func WriteEdgeList(w io.Writer, G EdgeLister) error {
+	bw := bufio.NewWriter(w)
+	kind := "undirected"
+	if G.Directed() {
+		kind = "directed"
+	}
+	fmt.Fprintf(bw, "%d %s\n", G.Order(), kind)
+	for e := range G.Edges() {
+		fmt.Fprintf(bw, "%d %d %s\n", e.From, e.To, formatFloat(e.Weight))
+	}
+	return bw.Flush()
+}
+
+// format a weight or attribute, so that it is read back exactly
+func formatFloat(x float64) string {
+	return strconv.FormatFloat(x, 'g', -1, 64)
+}
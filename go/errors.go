@@ -0,0 +1,46 @@
+/* This file contains the errors that are returned
+when a graph cannot be modified as requested. */
+
+package shortestpath
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errors returned by the graph mutation methods
+var (
+	ErrVertexRange   = errors.New("vertex out of range")
+	ErrDuplicateEdge = errors.New("duplicate edge")
+	ErrMissingEdge   = errors.New("edge does not exist")
+	ErrSelfLoop      = errors.New("self-loop")
+	ErrWeight        = errors.New("non-finite edge weight")
+	ErrAttributes    = errors.New("invalid edge attributes")
+	ErrCycle         = errors.New("graph contains a cycle")
+	ErrNegativeCycle = errors.New("graph contains a negative-weight cycle")
+)
+
+// ErrOrder is returned when a graph file has more vertices than MaxOrder or MaxCSROrder
+var ErrOrder = errors.New("too many vertices")
+
+// errors returned when a snapshot cannot be loaded
+var (
+	ErrSnapshot = errors.New("invalid snapshot")
+	ErrChecksum = errors.New("snapshot checksum mismatch")
+)
+
+// EdgeError records a failed operation on an edge (v1,v2).
+// The reason can be checked with errors.Is, e.g. errors.Is(err, ErrSelfLoop).
+type EdgeError struct {
+	Op  string // the failed operation, e.g. "AddEdge"
+	V1  int    // first vertex of the edge
+	V2  int    // second vertex of the edge
+	Err error  // the reason, one of the Err... variables
+}
+
+func (e *EdgeError) Error() string {
+	return fmt.Sprintf("%s (%d,%d): %v", e.Op, e.V1, e.V2, e.Err)
+}
+
+// Unwrap returns the reason of the error
+func (e *EdgeError) Unwrap() error { return e.Err }
@@ -0,0 +1,117 @@
+/* This file contains the common model behind the GraphML and
+node-link JSON formats, which are used to exchange graphs with
+other tools like Gephi or NetworkX. Both formats are converted
+from and to a Graph (weights and vertex labels) or an Attributed
+graph (all edge attributes). */
+
+package shortestpath
+
+import "math"
+
+// graphDoc is a graph as it is stored in an exchange format
+type graphDoc struct {
+	directed bool
+	labels   []string  // label of each vertex ("" if it has none)
+	names    []string  // names of the edge attributes
+	edges    []docEdge // all edges
+}
+
+// docEdge is an edge with the values of all attributes
+// (NaN if the attribute is missing)
+type docEdge struct {
+	from, to int
+	attrs    []float64
+}
+
+// the weights of a Graph are stored as this attribute
+const weightAttr = "weight"
+
+func docFromGraph(G *Graph) *graphDoc {
+	d := &graphDoc{directed: G.directed, names: []string{weightAttr}}
+	d.labels = make([]string, G.V)
+	for v := range d.labels {
+		d.labels[v] = G.Label(v)
+	}
+	for e := range G.Edges() {
+		d.edges = append(d.edges, docEdge{e.From, e.To, []float64{e.Weight}})
+	}
+	return d
+}
+
+func docFromAttributed(A *Attributed) *graphDoc {
+	d := &graphDoc{directed: A.Directed(), names: A.names}
+	d.labels = make([]string, A.Order())
+	for v := range d.labels {
+		d.labels[v] = A.Label(v)
+	}
+	for id, e := range A.EdgeIDs() {
+		d.edges = append(d.edges, docEdge{e.From, e.To, A.Attrs(id)})
+	}
+	return d
+}
+
+// set up a Graph, the weight is 1.0 if it is missing
+func (d *graphDoc) graph() (*Graph, error) {
+	if err := checkOrder(len(d.labels), MaxOrder); err != nil {
+		return nil, err
+	}
+	G := NewGraph()
+	G.directed = d.directed
+	G.SetOrder(len(d.labels))
+	if err := d.setLabels(G.SetLabel); err != nil {
+		return nil, err
+	}
+	w := -1
+	for i, name := range d.names {
+		if name == weightAttr {
+			w = i
+		}
+	}
+	for _, e := range d.edges {
+		l := 1.0
+		if w != -1 && !math.IsNaN(e.attrs[w]) {
+			l = e.attrs[w]
+		}
+		if err := G.AddEdge(e.from, e.to, l); err != nil {
+			return nil, err
+		}
+	}
+	return G, nil
+}
+
+// set the labels of a Graph or an Attributed graph
+func (d *graphDoc) setLabels(set func(v int, name string) error) error {
+	for v, name := range d.labels {
+		if name == "" {
+			continue
+		}
+		if err := set(v, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// set up an Attributed graph with labels, all attributes must be present
+func (d *graphDoc) attributed() (*Attributed, error) {
+	A := NewAttributed(len(d.labels), d.directed, d.names...)
+	if err := d.setLabels(A.SetLabel); err != nil {
+		return nil, err
+	}
+	for _, e := range d.edges {
+		if _, err := A.AddEdge(e.from, e.to, e.attrs...); err != nil {
+			return nil, err
+		}
+	}
+	return A, nil
+}
+
+// vertexLabel returns the label of a vertex read from a file: the label
+// itself if given, otherwise its ID unless it is the default ID of the
+// vertex (as written by the encoders)
+func vertexLabel(label, id, defaultID string) string {
+	if label != "" || id == defaultID {
+		return label
+	}
+	return id
+}
@@ -1,3 +0,0 @@
-module fibheap
-
-go 1.14
@@ -3,54 +3,43 @@ This file contains routines for performing the
 shortest-path search using a Floyd-Warshall algorithm.
 The Floyd-Warshall algorithm yields all-pair shortest-paths
 */
-package main
-
-import (
-	"fmt"
-	"math"
-)
-
-// a wrapper for the actual routine call
-func exampleFloydWarshall(G *Graph, start, end int) {
-
-	dist, prev := FloydWarshall(G)
-	fmt.Println("shortest path from vertex", start, "to vertex", end, ":")
-	if math.IsInf(dist[start][end], 0) {
-		err := fmt.Errorf("the selected vertex is not connected to the start point")
-		fmt.Println(err)
-	} else {
-		path := getPathFW(prev, start, end)
-		fmt.Println(path)
-
-		fmt.Println("with a total path length of", dist[start][end])
-	}
+package shortestpath
 
+// FloydWarshall is the implementation of the Floyd-Warshall algorithm.
+// G can be any Interface implementation, e.g. a Graph or a CSR graph.
+func FloydWarshall(G Interface) ([][]float64, [][]int) {
+	return FloydWarshallOf[float64](G)
 }
 
-// FloydWarshall is the implementation of the Floyd-Warshall algorithm
-func FloydWarshall(G *Graph) ([][]float64, [][]int) {
+// FloydWarshallOf is the same as FloydWarshall for graphs with edge weights of
+// type W, e.g. FloydWarshallOf[int64] for exact integer arithmetic.
+// The distance between unconnected vertices is Infinity[W]().
+func FloydWarshallOf[W Number](G Weighted[W]) ([][]W, [][]int) {
 
 	// The algorithm requires a V x V distance matrix
 	// with all the edge weights.
-	// This matrix is set up from the edge weight matrix G.Emat
-	// but elements not belonging to an edge get initialized with +Inf
+	// This matrix is set up from the edges of G,
+	// but elements not belonging to an edge get initialized with "infinity"
 	// Furthermore, for later path reconstruction we need a
 	// neighbour matrix prev. We can construct it from the
-	// adjacency matrix G.Nmat as such:
-	dist := make([][]float64, G.V)
-	prev := make([][]int, G.V)
+	// edges of G as well:
+	V := G.Order()
+	inf := Infinity[W]()
+	dist := make([][]W, V)
+	prev := make([][]int, V)
 	for i := range dist {
-		dist[i] = make([]float64, G.V)
-		prev[i] = make([]int, G.V)
+		dist[i] = make([]W, V)
+		prev[i] = make([]int, V)
 		for j := range dist[i] {
-			if G.Nmat[i][j] == 1 {
-				dist[i][j] = float64(G.Emat[i][j])
+			dist[i][j] = inf
+			prev[i][j] = -1
+		}
+		G.VisitEdges(i, func(j int, w W) {
+			if w < dist[i][j] { // for parallel edges the shortest one is used
+				dist[i][j] = w
 				prev[i][j] = j
-			} else {
-				dist[i][j] = math.Inf(0)
-				prev[i][j] = -1
 			}
-		}
+		})
 	}
 
 	// The algorithm is based on the following assumption:
@@ -58,10 +47,16 @@ func FloydWarshall(G *Graph) ([][]float64, [][]int) {
 	// vertex w, then the paths u-to-w and w-to-v are already minimal.
 	// Hence, the shorest paths are constructed by searching all path
 	// that run over an additional intermediate point k
-	var kdist float64
-	for k := 0; k < G.V; k++ {
-		for i := 0; i < G.V; i++ {
-			for j := 0; j < G.V; j++ {
+	var kdist W
+	for k := 0; k < V; k++ {
+		for i := 0; i < V; i++ {
+			if dist[i][k] == inf { // there is no path from i over k
+				continue
+			}
+			for j := 0; j < V; j++ {
+				if dist[k][j] == inf {
+					continue
+				}
 				kdist = dist[i][k] + dist[k][j]
 				if dist[i][j] > kdist { // if the path from i to j runs over k, update
 					dist[i][j] = kdist
@@ -74,8 +69,9 @@ func FloydWarshall(G *Graph) ([][]float64, [][]int) {
 	return dist, prev
 }
 
-// reconstruct the path
-func getPathFW(prev [][]int, u, v int) []int {
+// GetPathFW reconstructs the path from u to v using the
+// successor matrix prev returned by FloydWarshall
+func GetPathFW(prev [][]int, u, v int) []int {
 	var path []int
 	// if the vertices u and v are not connected prev[u][v] will be 0
 	if prev[u][v] == -1 {
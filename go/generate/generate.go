@@ -0,0 +1,374 @@
+// Package generate contains generators of random graphs for tests and
+// benchmarks: Erdős–Rényi G(n,p) and G(n,m), Barabási–Albert, Watts–Strogatz,
+// 2D/3D grids and random geometric graphs. Each generator takes its random
+// numbers from an explicit source, so that the graphs are reproducible,
+// and draws the edge weights from a given distribution.
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	shortestpath "github.com/pprcht/shortestpaths/go"
+)
+
+// ErrParameter is returned for parameters that don't describe a valid graph
+var ErrParameter = errors.New("invalid generator parameter")
+
+// Weights draws the weight of an edge. The length is the geometric length
+// of the edge for grids and geometric graphs, and 1 for the other graphs.
+type Weights func(r *rand.Rand, length float64) float64
+
+// Constant returns the same weight for all edges
+func Constant(w float64) Weights {
+	return func(r *rand.Rand, length float64) float64 { return w }
+}
+
+// Uniform returns weights that are uniformly distributed in [lo,hi)
+func Uniform(lo, hi float64) Weights {
+	return func(r *rand.Rand, length float64) float64 { return lo + (hi-lo)*r.Float64() }
+}
+
+// Exponential returns exponentially distributed weights with the given mean
+func Exponential(mean float64) Weights {
+	return func(r *rand.Rand, length float64) float64 { return mean * r.ExpFloat64() }
+}
+
+// Length uses the geometric length of the edges as weights,
+// multiplied with a random detour factor in [1,1+detour)
+// (e.g. 0.3 for roads, 0 for straight lines)
+func Length(detour float64) Weights {
+	return func(r *rand.Rand, length float64) float64 { return length * (1 + detour*r.Float64()) }
+}
+
+// Network is a generated graph
+type Network struct {
+	V        int                  // number of vertices
+	Directed bool                 // are the edges directed?
+	Edges    []shortestpath.Edge  // all edges, without parallel edges and self-loops
+	Coords   []shortestpath.Point // positions of the vertices of 2D grids and geometric graphs (nil otherwise)
+}
+
+// Graph returns the network as a Graph. Note that its
+// adjacency matrices need O(V²) memory, see CSR otherwise.
+func (N *Network) Graph() *shortestpath.Graph {
+	var G *shortestpath.Graph
+	if N.Directed {
+		G = shortestpath.NewDirectedGraph()
+	} else {
+		G = shortestpath.NewGraph()
+	}
+	G.SetOrder(N.V)
+	for _, e := range N.Edges {
+		G.AddEdge(e.From, e.To, e.Weight)
+	}
+	return G
+}
+
+// CSR returns the network as a CSR graph
+func (N *Network) CSR() *shortestpath.CSR {
+	return shortestpath.NewCSR(N.V, N.Directed, N.Edges)
+}
+
+// Generator generates random graphs
+type Generator struct {
+	rand    *rand.Rand
+	weights Weights
+}
+
+// New returns a generator that takes its random numbers from src
+// and draws the edge weights from the distribution w
+// (all weights are 1.0 if w is nil)
+func New(src rand.Source, w Weights) *Generator {
+	g := new(Generator)
+	g.rand = rand.New(src)
+	g.weights = w
+	if w == nil {
+		g.weights = Constant(1.0)
+	}
+	return g
+}
+
+// add an edge with a random weight
+func (g *Generator) add(N *Network, u, v int, length float64) {
+	N.Edges = append(N.Edges, shortestpath.Edge{From: u, To: v, Weight: g.weights(g.rand, length)})
+}
+
+// a pair of vertices, (u,v) with u < v for undirected graphs
+type pair struct{ u, v int }
+
+func newPair(u, v int, directed bool) pair {
+	if !directed && u > v {
+		u, v = v, u
+	}
+	return pair{u, v}
+}
+
+// GNP returns an Erdős–Rényi graph with n vertices, in which each
+// possible edge exists with probability p. It runs in O(n+m) for m edges
+// (Batagelj and Brandes, Efficient generation of large random networks, 2005).
+func (g *Generator) GNP(n int, p float64, directed bool) (*Network, error) {
+	if n < 0 || !(p >= 0 && p <= 1) {
+		return nil, fmt.Errorf("G(%d,%v): %w", n, p, ErrParameter)
+	}
+	N := &Network{V: n, Directed: directed}
+	if p == 0 || n < 2 {
+		return N, nil
+	}
+	// the number of pairs to skip until the next edge is geometrically distributed,
+	// it is limited to the number of pairs, as it can be huge for tiny p
+	pairs := n * (n - 1)
+	lp := math.Log1p(-p)
+	skip := func() int {
+		if p == 1 {
+			return 0
+		}
+		s := math.Log1p(-g.rand.Float64()) / lp
+		if s >= float64(pairs) {
+			return pairs
+		}
+		return int(s)
+	}
+	if directed {
+		// the pairs (u,v) with u != v are numbered from 0 to n(n-1)-1
+		for k := skip(); k < pairs; k += 1 + skip() {
+			u, v := k/(n-1), k%(n-1)
+			if v >= u {
+				v++
+			}
+			g.add(N, u, v, 1)
+		}
+		return N, nil
+	}
+	// the pairs (v,w) with w < v in the order of v
+	v, w := 1, -1
+	for v < n {
+		w += 1 + skip()
+		for w >= v && v < n {
+			w -= v
+			v++
+		}
+		if v < n {
+			g.add(N, w, v, 1)
+		}
+	}
+	return N, nil
+}
+
+// GNM returns an Erdős–Rényi graph with n vertices and
+// m edges, which are chosen uniformly from all possible edges
+func (g *Generator) GNM(n, m int, directed bool) (*Network, error) {
+	maxEdges := n * (n - 1)
+	if !directed {
+		maxEdges /= 2
+	}
+	if n < 0 || m < 0 || m > maxEdges {
+		return nil, fmt.Errorf("G(%d,%d): %w", n, m, ErrParameter)
+	}
+	N := &Network{V: n, Directed: directed}
+	random := func() pair {
+		for {
+			u, v := g.rand.Intn(n), g.rand.Intn(n)
+			if u != v {
+				return newPair(u, v, directed)
+			}
+		}
+	}
+	if m <= maxEdges/2 {
+		chosen := make(map[pair]bool, m)
+		for len(N.Edges) < m {
+			if e := random(); !chosen[e] {
+				chosen[e] = true
+				g.add(N, e.u, e.v, 1)
+			}
+		}
+		return N, nil
+	}
+	// for dense graphs it is faster to choose the missing edges
+	missing := make(map[pair]bool, maxEdges-m)
+	for len(missing) < maxEdges-m {
+		missing[random()] = true
+	}
+	for u := 0; u < n; u++ {
+		for v := 0; v < n; v++ {
+			if u != v && (directed || u < v) && !missing[pair{u, v}] {
+				g.add(N, u, v, 1)
+			}
+		}
+	}
+	return N, nil
+}
+
+// BarabasiAlbert returns a scale-free graph with n vertices grown by
+// preferential attachment: each new vertex gets k edges to existing
+// vertices, which are chosen with a probability proportional to their degree
+func (g *Generator) BarabasiAlbert(n, k int) (*Network, error) {
+	if k < 1 || n <= k {
+		return nil, fmt.Errorf("Barabási–Albert graph with n=%d, k=%d: %w", n, k, ErrParameter)
+	}
+	N := &Network{V: n}
+	// each vertex appears in repeated once for each of its edges,
+	// so that choosing uniformly from it is proportional to the degree
+	repeated := make([]int, 0, 2*n*k)
+	targets := make([]int, k)
+	for i := range targets {
+		targets[i] = i // the first new vertex connects to the k initial vertices
+	}
+	for s := k; s < n; s++ {
+		for _, t := range targets {
+			g.add(N, t, s, 1)
+			repeated = append(repeated, t, s)
+		}
+		// choose k distinct targets for the next vertex
+		chosen := make(map[int]bool, k)
+		targets = targets[:0]
+		for len(targets) < k {
+			t := repeated[g.rand.Intn(len(repeated))]
+			if !chosen[t] {
+				chosen[t] = true
+				targets = append(targets, t)
+			}
+		}
+	}
+	return N, nil
+}
+
+// WattsStrogatz returns a small-world graph with n vertices: a ring in
+// which each vertex is connected to its k nearest neighbours (k even),
+// and each edge is rewired to a random vertex with probability beta
+func (g *Generator) WattsStrogatz(n, k int, beta float64) (*Network, error) {
+	if k < 2 || k%2 != 0 || k >= n || !(beta >= 0 && beta <= 1) {
+		return nil, fmt.Errorf("Watts–Strogatz graph with n=%d, k=%d, beta=%v: %w", n, k, beta, ErrParameter)
+	}
+	edges := make([]pair, 0, n*k/2)
+	exists := make(map[pair]bool, n*k/2)
+	degree := make([]int, n)
+	for j := 1; j <= k/2; j++ {
+		for u := 0; u < n; u++ {
+			e := newPair(u, (u+j)%n, false)
+			edges = append(edges, e)
+			exists[e] = true
+			degree[u]++
+			degree[(u+j)%n]++
+		}
+	}
+	// rewire the far end of each edge, keeping the vertex u
+	for i := range edges {
+		u, v := i%n, (i%n+i/n+1)%n
+		if g.rand.Float64() >= beta || degree[u] == n-1 {
+			continue
+		}
+		w := g.rand.Intn(n)
+		for w == u || exists[newPair(u, w, false)] {
+			w = g.rand.Intn(n)
+		}
+		delete(exists, edges[i])
+		degree[v]--
+		edges[i] = newPair(u, w, false)
+		exists[edges[i]] = true
+		degree[w]++
+	}
+	N := &Network{V: n}
+	for _, e := range edges {
+		g.add(N, e.u, e.v, 1)
+	}
+	return N, nil
+}
+
+// Grid2D returns a grid of nx times ny vertices, in which each vertex is
+// connected to its horizontal and vertical neighbours. Vertex x+nx*y is
+// at the position (x,y), the edges have the length 1.
+func (g *Generator) Grid2D(nx, ny int) (*Network, error) {
+	if nx < 1 || ny < 1 {
+		return nil, fmt.Errorf("%dx%d grid: %w", nx, ny, ErrParameter)
+	}
+	N := &Network{V: nx * ny}
+	N.Coords = make([]shortestpath.Point, N.V)
+	for y := 0; y < ny; y++ {
+		for x := 0; x < nx; x++ {
+			v := x + nx*y
+			N.Coords[v] = shortestpath.Point{X: float64(x), Y: float64(y)}
+			if x > 0 {
+				g.add(N, v-1, v, 1)
+			}
+			if y > 0 {
+				g.add(N, v-nx, v, 1)
+			}
+		}
+	}
+	return N, nil
+}
+
+// Grid3D returns a grid of nx times ny times nz vertices, in which each
+// vertex is connected to its neighbours along the three axes.
+// Vertex x+nx*(y+ny*z) is at the position (x,y,z), the edges have the length 1.
+func (g *Generator) Grid3D(nx, ny, nz int) (*Network, error) {
+	if nx < 1 || ny < 1 || nz < 1 {
+		return nil, fmt.Errorf("%dx%dx%d grid: %w", nx, ny, nz, ErrParameter)
+	}
+	N := &Network{V: nx * ny * nz}
+	for z := 0; z < nz; z++ {
+		for y := 0; y < ny; y++ {
+			for x := 0; x < nx; x++ {
+				v := x + nx*(y+ny*z)
+				if x > 0 {
+					g.add(N, v-1, v, 1)
+				}
+				if y > 0 {
+					g.add(N, v-nx, v, 1)
+				}
+				if z > 0 {
+					g.add(N, v-nx*ny, v, 1)
+				}
+			}
+		}
+	}
+	return N, nil
+}
+
+// RandomGeometric returns a random geometric graph with n vertices placed
+// uniformly in the unit square, where two vertices are connected if their
+// distance is at most the radius. The edges have their euclidean length.
+func (g *Generator) RandomGeometric(n int, radius float64) (*Network, error) {
+	if n < 0 || !(radius >= 0) {
+		return nil, fmt.Errorf("random geometric graph with n=%d, radius=%v: %w", n, radius, ErrParameter)
+	}
+	N := &Network{V: n}
+	N.Coords = make([]shortestpath.Point, n)
+	for v := range N.Coords {
+		N.Coords[v] = shortestpath.Point{X: g.rand.Float64(), Y: g.rand.Float64()}
+	}
+	// sort the vertices into square cells with a side of at least the radius,
+	// so that only the neighbouring cells have to be searched
+	c := int(math.Ceil(math.Sqrt(float64(n))))
+	if radius > 0 {
+		c = min(c, int(1/radius))
+	}
+	c = max(c, 1)
+	cell := func(p shortestpath.Point) (int, int) {
+		return min(int(p.X*float64(c)), c-1), min(int(p.Y*float64(c)), c-1)
+	}
+	cells := make([][]int, c*c)
+	for v, p := range N.Coords {
+		x, y := cell(p)
+		cells[x+c*y] = append(cells[x+c*y], v)
+	}
+	for u, p := range N.Coords {
+		x, y := cell(p)
+		for cy := max(y-1, 0); cy <= min(y+1, c-1); cy++ {
+			for cx := max(x-1, 0); cx <= min(x+1, c-1); cx++ {
+				for _, v := range cells[cx+c*cy] {
+					if v <= u {
+						continue
+					}
+					if d := math.Hypot(p.X-N.Coords[v].X, p.Y-N.Coords[v].Y); d <= radius {
+						g.add(N, u, v, d)
+					}
+				}
+			}
+		}
+	}
+	return N, nil
+}
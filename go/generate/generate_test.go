@@ -0,0 +1,246 @@
+package generate
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	shortestpath "github.com/pprcht/shortestpaths/go"
+)
+
+// check that a network has no self-loops, parallel edges or invalid vertices
+func checkNetwork(t *testing.T, name string, N *Network) {
+	t.Helper()
+	seen := make(map[pair]bool)
+	for _, e := range N.Edges {
+		if e.From == e.To || e.From < 0 || e.To < 0 || e.From >= N.V || e.To >= N.V {
+			t.Errorf("%s: invalid edge %v", name, e)
+		}
+		p := newPair(e.From, e.To, N.Directed)
+		if seen[p] {
+			t.Errorf("%s: parallel edge %v", name, e)
+		}
+		seen[p] = true
+	}
+}
+
+func TestReproducible(t *testing.T) {
+	generators := map[string]func(g *Generator) (*Network, error){
+		"GNP":             func(g *Generator) (*Network, error) { return g.GNP(100, 0.05, true) },
+		"GNM":             func(g *Generator) (*Network, error) { return g.GNM(100, 300, false) },
+		"BarabasiAlbert":  func(g *Generator) (*Network, error) { return g.BarabasiAlbert(100, 3) },
+		"WattsStrogatz":   func(g *Generator) (*Network, error) { return g.WattsStrogatz(100, 4, 0.2) },
+		"Grid2D":          func(g *Generator) (*Network, error) { return g.Grid2D(10, 10) },
+		"RandomGeometric": func(g *Generator) (*Network, error) { return g.RandomGeometric(100, 0.15) },
+	}
+	for name, generate := range generators {
+		N1, err1 := generate(New(rand.NewSource(42), Uniform(1, 10)))
+		N2, err2 := generate(New(rand.NewSource(42), Uniform(1, 10)))
+		N3, err3 := generate(New(rand.NewSource(43), Uniform(1, 10)))
+		if err1 != nil || err2 != nil || err3 != nil {
+			t.Errorf("%s failed: %v, %v, %v", name, err1, err2, err3)
+			continue
+		}
+		checkNetwork(t, name, N1)
+		if !slices.Equal(N1.Edges, N2.Edges) || !slices.Equal(N1.Coords, N2.Coords) {
+			t.Errorf("%s is not reproducible with the same seed", name)
+		}
+		if slices.Equal(N1.Edges, N3.Edges) {
+			t.Errorf("%s incorrect, got the same graph for different seeds", name)
+		}
+		for _, e := range N1.Edges {
+			if e.Weight < 1 || e.Weight >= 10 {
+				t.Errorf("%s incorrect, got weight %v, want [1,10)", name, e.Weight)
+				break
+			}
+		}
+	}
+}
+
+func TestErdosRenyi(t *testing.T) {
+	g := New(rand.NewSource(1), nil)
+	for _, directed := range []bool{false, true} {
+		n, p := 200, 0.1
+		N, err := g.GNP(n, p, directed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkNetwork(t, "GNP", N)
+		want := p * float64(n*(n-1))
+		if !directed {
+			want /= 2
+		}
+		if got := float64(len(N.Edges)); math.Abs(got-want) > 4*math.Sqrt(want) {
+			t.Errorf("number of edges of G(n,p) incorrect, got %v, want about %v", got, want)
+		}
+
+		N, _ = g.GNP(20, 1, directed)
+		if got, want := len(N.Edges), 20*19/map[bool]int{false: 2, true: 1}[directed]; got != want {
+			t.Errorf("number of edges of the complete graph incorrect, got %v, want %v", got, want)
+		}
+		checkNetwork(t, "GNP", N)
+
+		// sparse and dense graphs are generated differently
+		for _, m := range []int{50, 180} {
+			N, err := g.GNM(20, m, directed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkNetwork(t, "GNM", N)
+			if len(N.Edges) != m {
+				t.Errorf("number of edges of G(n,m) incorrect, got %v, want %v", len(N.Edges), m)
+			}
+		}
+	}
+	// 1-p rounds to 1 for tiny p
+	for _, directed := range []bool{false, true} {
+		N, err := g.GNP(10, 1e-17, directed)
+		if err != nil || len(N.Edges) != 0 {
+			t.Errorf("G(n,p) with tiny p incorrect, got %v (%v), want no edges", N.Edges, err)
+		}
+	}
+	if _, err := g.GNM(20, 191, false); !errors.Is(err, ErrParameter) {
+		t.Errorf("G(n,m) with too many edges incorrect, got %v, want %v", err, ErrParameter)
+	}
+	if _, err := g.GNP(20, 1.5, false); !errors.Is(err, ErrParameter) {
+		t.Errorf("G(n,p) with p > 1 incorrect, got %v, want %v", err, ErrParameter)
+	}
+}
+
+func TestBarabasiAlbert(t *testing.T) {
+	n, k := 500, 3
+	N, err := New(rand.NewSource(1), nil).BarabasiAlbert(n, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkNetwork(t, "BarabasiAlbert", N)
+	if got, want := len(N.Edges), (n-k)*k; got != want {
+		t.Errorf("number of edges incorrect, got %v, want %v", got, want)
+	}
+	degree := make([]int, n)
+	for _, e := range N.Edges {
+		degree[e.From]++
+		degree[e.To]++
+	}
+	// the degrees are very uneven in a scale-free graph
+	if got := slices.Max(degree); got < 5*k {
+		t.Errorf("maximal degree incorrect, got %v, want at least %v", got, 5*k)
+	}
+}
+
+func TestWattsStrogatz(t *testing.T) {
+	g := New(rand.NewSource(1), nil)
+	n, k := 100, 6
+	N, err := g.WattsStrogatz(n, k, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// without rewiring it's a ring lattice
+	for _, e := range N.Edges {
+		if d := (e.To - e.From + n) % n; min(d, n-d) > k/2 {
+			t.Errorf("edge of the ring lattice incorrect, got %v", e)
+		}
+	}
+	N, err = g.WattsStrogatz(n, k, 0.3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkNetwork(t, "WattsStrogatz", N)
+	if got, want := len(N.Edges), n*k/2; got != want {
+		t.Errorf("number of edges incorrect, got %v, want %v", got, want)
+	}
+	if _, err := g.WattsStrogatz(n, 5, 0.3); !errors.Is(err, ErrParameter) {
+		t.Errorf("odd k incorrect, got %v, want %v", err, ErrParameter)
+	}
+}
+
+func TestGrid(t *testing.T) {
+	g := New(rand.NewSource(1), Length(0))
+	nx, ny, nz := 7, 5, 3
+	N, err := g.Grid2D(nx, ny)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkNetwork(t, "Grid2D", N)
+	if got, want := len(N.Edges), (nx-1)*ny+nx*(ny-1); got != want {
+		t.Errorf("number of edges of the 2D grid incorrect, got %v, want %v", got, want)
+	}
+	if got, want := N.Coords[nx*ny-1], (shortestpath.Point{X: float64(nx - 1), Y: float64(ny - 1)}); got != want {
+		t.Errorf("coordinates incorrect, got %v, want %v", got, want)
+	}
+	dist, _ := shortestpath.Dijkstra(N.Graph(), 0)
+	if got, want := dist[nx*ny-1], float64(nx+ny-2); got != want {
+		t.Errorf("distance between the corners incorrect, got %v, want %v", got, want)
+	}
+
+	N, err = g.Grid3D(nx, ny, nz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkNetwork(t, "Grid3D", N)
+	if got, want := len(N.Edges), (nx-1)*ny*nz+nx*(ny-1)*nz+nx*ny*(nz-1); got != want {
+		t.Errorf("number of edges of the 3D grid incorrect, got %v, want %v", got, want)
+	}
+	dist, _ = shortestpath.Dijkstra(N.CSR(), 0)
+	if got, want := dist[N.V-1], float64(nx+ny+nz-3); got != want {
+		t.Errorf("distance between the corners incorrect, got %v, want %v", got, want)
+	}
+}
+
+func TestRandomGeometric(t *testing.T) {
+	for _, radius := range []float64{0, 0.01, 0.2, 2} {
+		N, err := New(rand.NewSource(1), Length(0)).RandomGeometric(300, radius)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkNetwork(t, "RandomGeometric", N)
+		// compare with all pairs of vertices
+		want := 0
+		for u := range N.Coords {
+			for v := u + 1; v < N.V; v++ {
+				if math.Hypot(N.Coords[u].X-N.Coords[v].X, N.Coords[u].Y-N.Coords[v].Y) <= radius {
+					want++
+				}
+			}
+		}
+		if len(N.Edges) != want {
+			t.Errorf("number of edges for radius %v incorrect, got %v, want %v", radius, len(N.Edges), want)
+		}
+		for _, e := range N.Edges {
+			a, b := N.Coords[e.From], N.Coords[e.To]
+			if d := math.Hypot(a.X-b.X, a.Y-b.Y); e.Weight != d {
+				t.Errorf("weight of edge %v incorrect, got %v, want %v", e, e.Weight, d)
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkDijkstraGrid(b *testing.B) {
+	N, _ := New(rand.NewSource(1992), Uniform(1, 2)).Grid2D(100, 100)
+	C := N.CSR()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		shortestpath.DijkstraFibonacci(C, 0)
+	}
+}
+
+func BenchmarkDijkstraGeometric(b *testing.B) {
+	N, _ := New(rand.NewSource(1992), Length(0.3)).RandomGeometric(10000, 0.02)
+	C := N.CSR()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		shortestpath.DijkstraFibonacci(C, 0)
+	}
+}
+
+func BenchmarkDijkstraBarabasiAlbert(b *testing.B) {
+	N, _ := New(rand.NewSource(1992), Exponential(1)).BarabasiAlbert(10000, 3)
+	C := N.CSR()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		shortestpath.DijkstraFibonacci(C, 0)
+	}
+}
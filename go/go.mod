@@ -1,7 +1,3 @@
-module shortestpath
+module github.com/pprcht/shortestpaths/go
 
-go 1.14
-
-replace fibheap => ./fibheap
-
-require fibheap v0.0.0-00010101000000-000000000000
+go 1.23
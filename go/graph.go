@@ -3,20 +3,72 @@ As they only serve as an example there is nothing fancy
 about them and could probably be written more efficiently.
 Also the graph dimension must not be too large. */
 
-package main
+// Package shortestpath contains a simple graph type and some
+// algorithms to find the shortest path between its vertices.
+package shortestpath
 
-import "math/rand"
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+)
 
-//Graph is an object containing a set of vertices and edges
+//Graph is an object containing a set of vertices and edges.
+//The matrices are only changed by the methods of Graph, which keep
+//them in sync with the neighbour lists, use HasEdge and Weight to read them.
 type Graph struct {
 	V        int         // number of vertices (order of the graph)
 	E        int         // number of edges (size of the graph)
-	Nmat     [][]int     // neighbour matrix (adjacency matrix)
-	Emat     [][]float32 // edge matrix (edge weights)
+	nmat     [][]int     // neighbour matrix (adjacency matrix)
+	emat     [][]float64 // edge matrix (edge weights)
+	adj      [][]int     // sorted neighbour lists (rows of nmat)
 	directed bool        // is the graph a directed graph?
+	parallel Parallel    // what to do when adding an existing edge
+	labels   *Labels     // external identifiers of the vertices
+	removed  []bool      // vertices deleted by DelVertex (until Compact)
 }
 
-func newGraph() *Graph {
+// Parallel is the policy that decides what happens
+// if an edge that already exists is added again
+type Parallel int
+
+// policies for parallel edges
+const (
+	RejectParallel Parallel = iota // return an ErrDuplicateEdge error (default)
+	KeepFirst                      // keep the weight of the existing edge
+	KeepMin                        // keep the smaller of both weights
+	Replace                        // use the weight of the new edge
+	Sum                            // use the sum of both weights
+)
+
+// merge the weight of an existing edge (old)
+// and a parallel edge (l) according to the policy
+func merge[W Number](p Parallel, old, l W) W {
+	switch p {
+	case KeepMin:
+		if l < old {
+			return l
+		}
+		return old
+	case Replace:
+		return l
+	case Sum:
+		return old + l
+	}
+	return old
+}
+
+// Interface is what the shortest-path routines need to know about
+// a graph: its order and the (weighted) edges leaving each vertex.
+// It is implemented by Graph and CSR, but any other graph storage
+// (database-backed, implicit, memory-mapped, ...) can be used
+// with the algorithms by implementing it (see Weighted for the methods).
+type Interface = Weighted[float64]
+
+// NewGraph returns an empty (undirected) graph.
+// Use SetOrder to set the number of vertices before adding edges.
+func NewGraph() *Graph {
 	G := new(Graph)
 	G.V = 0
 	G.E = 0
@@ -24,66 +76,269 @@ func newGraph() *Graph {
 	return G
 }
 
-// set total number of vertices
-func (G *Graph) setOrder(i int) {
+// NewDirectedGraph returns an empty directed graph.
+// An edge (v1,v2) of a directed graph can only be walked from v1 to v2.
+// Use SetOrder to set the number of vertices before adding edges.
+func NewDirectedGraph() *Graph {
+	G := NewGraph()
+	G.directed = true
+	return G
+}
+
+// SetParallel sets the policy that is used by AddEdge
+// for edges that are already part of the graph
+func (G *Graph) SetParallel(p Parallel) {
+	G.parallel = p
+}
+
+// Directed reports whether G is a directed graph
+func (G *Graph) Directed() bool {
+	return G.directed
+}
+
+// SetOrder sets the total number of vertices.
+// All previously added edges and vertex labels are removed.
+func (G *Graph) SetOrder(i int) {
 	G.V = i
 	a := make([][]int, G.V)
 	for i := range a {
 		a[i] = make([]int, G.V)
 	}
-	G.Nmat = a
-	b := make([][]float32, G.V)
+	G.nmat = a
+	b := make([][]float64, G.V)
 	for i := range b {
-		b[i] = make([]float32, G.V)
+		b[i] = make([]float64, G.V)
+	}
+	G.emat = b
+	G.adj = make([][]int, G.V)
+	G.E = 0
+	G.labels = nil
+	G.removed = nil
+}
+
+// AddVertex adds a new (unconnected) vertex to the graph
+// and returns its index. Existing edges are kept.
+func (G *Graph) AddVertex() int {
+	return G.AddVertices(1)
+}
+
+// AddVertices adds k new (unconnected) vertices to the graph
+// and returns the index of the first one. Existing edges are kept.
+func (G *Graph) AddVertices(k int) int {
+	first := G.V
+	if k < 1 {
+		return first
+	}
+	n := G.V + k
+	for i := 0; i < G.V; i++ {
+		G.nmat[i] = append(G.nmat[i], make([]int, k)...)
+		G.emat[i] = append(G.emat[i], make([]float64, k)...)
 	}
-	G.Emat = b
+	for i := G.V; i < n; i++ {
+		G.nmat = append(G.nmat, make([]int, n))
+		G.emat = append(G.emat, make([]float64, n))
+		G.adj = append(G.adj, nil)
+	}
+	if G.removed != nil {
+		G.removed = append(G.removed, make([]bool, k)...)
+	}
+	G.V = n
+	return first
 }
 
-// add a new edge between two vertices,
+// DelVertex removes all edges and the label of a vertex and marks
+// it as deleted, so no new edges can be attached to it.
+// The indices of the other vertices do not change until Compact is called.
+func (G *Graph) DelVertex(v int) error {
+	if err := G.DisconnectVert(v); err != nil {
+		return err
+	}
+	if G.removed == nil {
+		G.removed = make([]bool, G.V)
+	}
+	G.removed[v] = true
+	if G.labels != nil {
+		G.labels.Set(v, "")
+	}
+	return nil
+}
+
+// Deleted reports whether vertex v has been deleted with DelVertex
+func (G *Graph) Deleted(v int) bool {
+	return G.removed != nil && v >= 0 && v < G.V && G.removed[v]
+}
+
+// Compact removes all deleted vertices from the graph and renumbers
+// the remaining ones (keeping their order, edges and labels).
+// It returns a table with the new index of each old vertex,
+// which is -1 for the deleted vertices.
+func (G *Graph) Compact() []int {
+	remap := make([]int, G.V)
+	n := 0
+	for v := 0; v < G.V; v++ {
+		if G.Deleted(v) {
+			remap[v] = -1
+		} else {
+			remap[v] = n
+			n++
+		}
+	}
+	a := make([][]int, n)
+	b := make([][]float64, n)
+	for i := 0; i < G.V; i++ {
+		if remap[i] == -1 {
+			continue
+		}
+		a[remap[i]] = make([]int, n)
+		b[remap[i]] = make([]float64, n)
+		for j := 0; j < G.V; j++ {
+			if remap[j] != -1 {
+				a[remap[i]][remap[j]] = G.nmat[i][j]
+				b[remap[i]][remap[j]] = G.emat[i][j]
+			}
+		}
+	}
+	G.nmat = a
+	G.emat = b
+	adj := make([][]int, n)
+	for i := 0; i < G.V; i++ {
+		if remap[i] == -1 {
+			continue
+		}
+		for _, j := range G.adj[i] {
+			adj[remap[i]] = append(adj[remap[i]], remap[j])
+		}
+	}
+	G.adj = adj
+	if G.labels != nil {
+		L := NewLabels()
+		for v := 0; v < G.V; v++ {
+			if name := G.labels.Name(v); remap[v] != -1 && name != "" {
+				L.Set(remap[v], name)
+			}
+		}
+		G.labels = L
+	}
+	G.V = n
+	G.removed = nil
+	return remap
+}
+
+// AddEdge adds a new edge between two vertices,
 // but only if the vertices are within the order of G
-// Each edge requires a value (edge length)
-// Setting up parallel edges is avoided.
-func (G *Graph) addEdge(v1, v2 int, l float64) {
+// Each edge requires a value (edge length), which must be finite.
+// Setting up parallel edges or self-loops is avoided, if the edge
+// already exists its weight is updated according to the policy set
+// with SetParallel (by default an ErrDuplicateEdge error is returned).
+// If the edge cannot be added an *EdgeError is returned.
+func (G *Graph) AddEdge(v1, v2 int, l float64) error {
 	var e []int
-	if v1 < G.V && v2 < G.V {
-		if G.directed {
-			e = append(e, v1, v2)
-		} else { // if the graph is not directed, sort the edge
-			e = edge(v1, v2)
+	if !G.hasVertex(v1) || !G.hasVertex(v2) {
+		return &EdgeError{"AddEdge", v1, v2, ErrVertexRange}
+	}
+	if v1 == v2 {
+		return &EdgeError{"AddEdge", v1, v2, ErrSelfLoop}
+	}
+	if math.IsNaN(l) || math.IsInf(l, 0) {
+		return &EdgeError{"AddEdge", v1, v2, ErrWeight}
+	}
+	if G.directed {
+		e = append(e, v1, v2)
+	} else { // if the graph is not directed, sort the edge
+		e = edge(v1, v2)
+	}
+	if G.HasEdge(e[0], e[1]) {
+		if G.parallel == RejectParallel {
+			return &EdgeError{"AddEdge", v1, v2, ErrDuplicateEdge}
 		}
-		if !G.checkEdges(e) {
-			G.Nmat[e[0]][e[1]] = 1
-			G.Emat[e[0]][e[1]] = float32(l)
-			if !G.directed { // for undirected graphs the matrices are symmetric
-				G.Nmat[e[1]][e[0]] = 1
-				G.Emat[e[1]][e[0]] = float32(l)
-			}
-			G.E++ //update size of G
+		l = merge(G.parallel, G.emat[e[0]][e[1]], l)
+		if err := G.SetWeight(e[0], e[1], l); err != nil {
+			return &EdgeError{"AddEdge", v1, v2, errors.Unwrap(err)}
 		}
-	} else {
-		// err := fmt.Errorf("Vertex does not exist")
-		// return err
+		return nil
+	}
+	G.nmat[e[0]][e[1]] = 1
+	G.emat[e[0]][e[1]] = l
+	G.adj[e[0]] = insertSorted(G.adj[e[0]], e[1])
+	if !G.directed { // for undirected graphs the matrices are symmetric
+		G.nmat[e[1]][e[0]] = 1
+		G.emat[e[1]][e[0]] = l
+		G.adj[e[1]] = insertSorted(G.adj[e[1]], e[0])
 	}
+	G.E++ //update size of G
+	return nil
 }
 
-// run a check on a graph: is a given edge part of the graph?
-func (G *Graph) checkEdges(edge []int) bool {
-	v1 := edge[0]
-	v2 := edge[1]
-	if G.Nmat[v1][v2] == 1 {
+// HasEdge runs a check on a graph: is a given edge part of the graph?
+func (G *Graph) HasEdge(v1, v2 int) bool {
+	if G.hasVertex(v1) && G.hasVertex(v2) && G.nmat[v1][v2] == 1 {
 		return true
 	}
 	return false
 }
 
-// delete an edge between two vertices (if it is present) from the graph
-func (G *Graph) delEdge(v1, v2 int) {
-	G.Nmat[v1][v2] = 0
-	G.Emat[v1][v2] = 0
+// SetWeight updates the weight (length) of an existing edge.
+// If the edge is not present or the weight is not finite
+// an *EdgeError is returned.
+func (G *Graph) SetWeight(v1, v2 int, l float64) error {
+	if !G.hasVertex(v1) || !G.hasVertex(v2) {
+		return &EdgeError{"SetWeight", v1, v2, ErrVertexRange}
+	}
+	if !G.HasEdge(v1, v2) {
+		return &EdgeError{"SetWeight", v1, v2, ErrMissingEdge}
+	}
+	if math.IsNaN(l) || math.IsInf(l, 0) {
+		return &EdgeError{"SetWeight", v1, v2, ErrWeight}
+	}
+	G.emat[v1][v2] = l
+	if !G.directed {
+		G.emat[v2][v1] = l
+	}
+	return nil
+}
+
+// is the vertex within the order of the graph (and not deleted)?
+func (G *Graph) hasVertex(v int) bool {
+	return v >= 0 && v < G.V && !G.Deleted(v)
+}
+
+// DelEdge deletes an edge between two vertices from the graph.
+// If the edge is not present an *EdgeError is returned.
+func (G *Graph) DelEdge(v1, v2 int) error {
+	if !G.hasVertex(v1) || !G.hasVertex(v2) {
+		return &EdgeError{"DelEdge", v1, v2, ErrVertexRange}
+	}
+	if !G.HasEdge(v1, v2) {
+		return &EdgeError{"DelEdge", v1, v2, ErrMissingEdge}
+	}
+	G.nmat[v1][v2] = 0
+	G.emat[v1][v2] = 0
+	G.adj[v1] = removeSorted(G.adj[v1], v2)
 	if !G.directed {
-		G.Nmat[v2][v1] = 0
-		G.Emat[v2][v1] = 0
+		G.nmat[v2][v1] = 0
+		G.emat[v2][v1] = 0
+		G.adj[v2] = removeSorted(G.adj[v2], v1)
+	}
+	G.E-- //update size of G
+	return nil
+}
+
+// insert a vertex into a sorted neighbour list
+func insertSorted(list []int, v int) []int {
+	i := sort.SearchInts(list, v)
+	list = append(list, 0)
+	copy(list[i+1:], list[i:])
+	list[i] = v
+	return list
+}
+
+// remove a vertex from a sorted neighbour list
+func removeSorted(list []int, v int) []int {
+	i := sort.SearchInts(list, v)
+	if i < len(list) && list[i] == v {
+		list = append(list[:i], list[i+1:]...)
 	}
+	return list
 }
 
 // quickly convert a pair of two vertices into
@@ -98,119 +353,176 @@ func edge(v1, v2 int) []int {
 	return e
 }
 
-// Get the weight (length) of an edge
-func (G *Graph) getWeight(v1, v2 int) float32 {
-	return G.Emat[v1][v2]
+// Weight returns the weight (length) of an edge
+func (G *Graph) Weight(v1, v2 int) float64 {
+	return G.emat[v1][v2]
+}
+
+// Degree returns the degree of a vertex (i.e, the number of its connected neighbours)
+// it is equal to the sum of row v of the adjacency matrix.
+// For directed graphs this is the out-degree.
+func (G *Graph) Degree(v int) int {
+	return len(G.adj[v])
 }
 
-// Get the degree of a vertex (i.e, the number of its connected neighbours)
-// it is equal to the sum of row v of the adjacency matrix
-func (G *Graph) degree(v int) int {
+// Neighbours returns a list of the connected neighbours of a vertex
+// and also outputs the degree of the vertex.
+// For directed graphs these are the successors of the vertex.
+func (G *Graph) Neighbours(v int) ([]int, int) {
+	/* this works for directed and undirected graphs
+	since G.adj[v] is the row of the adjacency matrix
+	belonging to a single vertex v */
+	nei := append([]int(nil), G.adj[v]...)
+	return nei, len(nei)
+}
+
+// OutDegree returns the number of edges leaving a vertex
+// (equal to Degree for undirected graphs)
+func (G *Graph) OutDegree(v int) int {
+	return G.Degree(v)
+}
+
+// InDegree returns the number of edges entering a vertex,
+// it is equal to the sum of column v of the adjacency matrix
+// (and to Degree for undirected graphs).
+func (G *Graph) InDegree(v int) int {
 	deg := 0
-	for _, k := range G.Nmat[v] {
-		if k == 1 {
+	for i := 0; i < G.V; i++ {
+		if G.nmat[i][v] == 1 {
 			deg++
 		}
 	}
 	return deg
 }
 
-// Get a list of the connected neighbours of a vertex
-// also outputs the degree of the vertex
-func (G *Graph) neighbours(v int) ([]int, int) {
-	var nei []int
-	deg := 0
-	/* this should work for directed and undirected graphs
-	since G.Nmat[v] is the part of the adjacency matrix
-	belonging to a single vertex v */
-	for i, k := range G.Nmat[v] {
-		if k == 1 {
-			nei = append(nei, i)
-			deg++
+// Successors returns a list of the vertices that can be reached
+// from v via a single edge (v,w)
+func (G *Graph) Successors(v int) []int {
+	nei, _ := G.Neighbours(v)
+	return nei
+}
+
+// Predecessors returns a list of the vertices from which v
+// can be reached via a single edge (w,v)
+func (G *Graph) Predecessors(v int) []int {
+	var pre []int
+	for i := 0; i < G.V; i++ {
+		if G.nmat[i][v] == 1 {
+			pre = append(pre, i)
 		}
 	}
-	return nei, deg
+	return pre
+}
+
+// Order returns the number of vertices (order of the graph)
+func (G *Graph) Order() int { return G.V }
+
+// VisitEdges calls fn for every neighbour v of vertex u
+// together with the weight of the edge (u,v)
+func (G *Graph) VisitEdges(u int, fn func(v int, w float64)) {
+	for _, v := range G.adj[u] {
+		fn(v, G.emat[u][v])
+	}
 }
 
 // Nlist is a function that returns a list of all neighbours for each vertex
 func (G *Graph) Nlist() [][]int {
 	neigh := make([][]int, G.V)
 	for i := 0; i < G.V; i++ {
-		neigh[i], _ = G.neighbours(i) // get the neighbour lists for easy access
+		neigh[i], _ = G.Neighbours(i) // get the neighbour lists for easy access
 	}
 	return neigh
 }
 
-// disconnect a vertex from the graph
-// (i.e., remove all its edges)
-func (G *Graph) disconnectVert(v int) {
-	nei, _ := G.neighbours(v)
+// DisconnectVert disconnects a vertex from the graph
+// (i.e., removes all its edges, for directed graphs
+// both the outgoing and incoming ones)
+func (G *Graph) DisconnectVert(v int) error {
+	if !G.hasVertex(v) {
+		return &EdgeError{"DisconnectVert", v, v, ErrVertexRange}
+	}
+	nei, _ := G.Neighbours(v)
 	for _, k := range nei {
-		G.delEdge(v, k)
+		if err := G.DelEdge(v, k); err != nil {
+			return err
+		}
 	}
+	if G.directed {
+		for _, k := range G.Predecessors(v) {
+			if err := G.DelEdge(k, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
-// set up the example graph depicted in assets/graph_1
+// Example1 sets up the example graph depicted in assets/graph_1
 // with the same edge weight for all edges
-func (G *Graph) example1() {
-	G.setOrder(16)
+func (G *Graph) Example1() {
+	G.SetOrder(16)
 	l := 1.0 // default edge weight for all edges
-	G.addEdge(0, 1, l)
-	G.addEdge(0, 4, l)
-	G.addEdge(1, 2, l)
-	G.addEdge(2, 3, l)
-	G.addEdge(2, 7, l)
-	G.addEdge(3, 4, l)
-	G.addEdge(3, 5, l)
-	G.addEdge(4, 8, l)
-	G.addEdge(5, 6, l)
-	G.addEdge(5, 9, l)
-	G.addEdge(5, 10, l)
-	G.addEdge(6, 7, l)
-	G.addEdge(6, 14, l)
-	G.addEdge(8, 9, l)
-	G.addEdge(10, 11, l)
-	G.addEdge(10, 12, l)
-	G.addEdge(11, 12, l)
-	G.addEdge(11, 13, l)
-	G.addEdge(12, 14, l)
-	G.addEdge(13, 14, l)
-	G.addEdge(14, 15, l)
-}
-
-// set up the example graph depicted in assets/graph_2
+	G.AddEdge(0, 1, l)
+	G.AddEdge(0, 4, l)
+	G.AddEdge(1, 2, l)
+	G.AddEdge(2, 3, l)
+	G.AddEdge(2, 7, l)
+	G.AddEdge(3, 4, l)
+	G.AddEdge(3, 5, l)
+	G.AddEdge(4, 8, l)
+	G.AddEdge(5, 6, l)
+	G.AddEdge(5, 9, l)
+	G.AddEdge(5, 10, l)
+	G.AddEdge(6, 7, l)
+	G.AddEdge(6, 14, l)
+	G.AddEdge(8, 9, l)
+	G.AddEdge(10, 11, l)
+	G.AddEdge(10, 12, l)
+	G.AddEdge(11, 12, l)
+	G.AddEdge(11, 13, l)
+	G.AddEdge(12, 14, l)
+	G.AddEdge(13, 14, l)
+	G.AddEdge(14, 15, l)
+}
+
+// Example2 sets up the example graph depicted in assets/graph_2
 // with different weights for all edges
-func (G *Graph) example2() {
-	G.setOrder(16)
-	G.addEdge(0, 1, 1.85)
-	G.addEdge(0, 4, 1.36)
-	G.addEdge(1, 2, 1.51)
-	G.addEdge(2, 3, 2.14)
-	G.addEdge(2, 7, 1.59)
-	G.addEdge(3, 4, 0.55)
-	G.addEdge(3, 5, 0.80)
-	G.addEdge(4, 8, 0.91)
-	G.addEdge(5, 6, 1.12)
-	G.addEdge(5, 9, 1.05)
-	G.addEdge(5, 10, 1.12)
-	G.addEdge(6, 7, 0.92)
-	G.addEdge(6, 14, 1.76)
-	G.addEdge(8, 9, 0.78)
-	G.addEdge(10, 11, 1.00)
-	G.addEdge(10, 12, 0.50)
-	G.addEdge(11, 12, 0.45)
-	G.addEdge(11, 13, 1.87)
-	G.addEdge(12, 14, 1.27)
-	G.addEdge(13, 14, 1.64)
-	G.addEdge(14, 15, 1.12)
+func (G *Graph) Example2() {
+	G.SetOrder(16)
+	G.AddEdge(0, 1, 1.85)
+	G.AddEdge(0, 4, 1.36)
+	G.AddEdge(1, 2, 1.51)
+	G.AddEdge(2, 3, 2.14)
+	G.AddEdge(2, 7, 1.59)
+	G.AddEdge(3, 4, 0.55)
+	G.AddEdge(3, 5, 0.80)
+	G.AddEdge(4, 8, 0.91)
+	G.AddEdge(5, 6, 1.12)
+	G.AddEdge(5, 9, 1.05)
+	G.AddEdge(5, 10, 1.12)
+	G.AddEdge(6, 7, 0.92)
+	G.AddEdge(6, 14, 1.76)
+	G.AddEdge(8, 9, 0.78)
+	G.AddEdge(10, 11, 1.00)
+	G.AddEdge(10, 12, 0.50)
+	G.AddEdge(11, 12, 0.45)
+	G.AddEdge(11, 13, 1.87)
+	G.AddEdge(12, 14, 1.27)
+	G.AddEdge(13, 14, 1.64)
+	G.AddEdge(14, 15, 1.12)
 }
 
 //RandomGraph is used to generate a random graph with NV vertices
-//each vertex will randomly get NE edges to other vertices
+//each vertex will randomly get NE edges to other vertices (duplicates are dropped)
 //For simplicity all edges will get the same weight (= 1.0)
+//It uses the global random source, see the generate package
+//for reproducible graphs with other shapes and weights.
 func RandomGraph(NV, ne int) *Graph {
-	G := newGraph()
-	G.setOrder(NV)
+	G := NewGraph()
+	G.SetOrder(NV)
+	if NV < 2 {
+		return G // there is no other vertex to connect to
+	}
 
 	var k int
 	var r int
@@ -219,7 +531,7 @@ func RandomGraph(NV, ne int) *Graph {
 		for k < ne {
 			r = rand.Intn(NV)
 			if r != i {
-				G.addEdge(i, r, 1.00)
+				G.AddEdge(i, r, 1.00)
 				k++
 			}
 		}
@@ -0,0 +1,1014 @@
+package shortestpath
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+/*
+  Some tests for setting up and modifying graphs
+*/
+
+// the mutation methods must report errors and keep track of the size
+func TestGraphErrors(t *testing.T) {
+	G := NewGraph()
+	G.Example2()
+	tests := []struct {
+		v1, v2 int
+		l      float64
+		want   error
+	}{
+		{0, 16, 1.0, ErrVertexRange},
+		{-1, 2, 1.0, ErrVertexRange},
+		{1, 0, 1.0, ErrDuplicateEdge},
+		{3, 3, 1.0, ErrSelfLoop},
+		{0, 2, math.NaN(), ErrWeight},
+		{0, 2, math.Inf(-1), ErrWeight},
+		{0, 2, math.Inf(1), ErrWeight},
+	}
+	for _, tt := range tests {
+		err := G.AddEdge(tt.v1, tt.v2, tt.l)
+		var eerr *EdgeError
+		if !errors.Is(err, tt.want) || !errors.As(err, &eerr) || eerr.V1 != tt.v1 {
+			t.Errorf("AddEdge(%d, %d, %g) returned %v, want %v", tt.v1, tt.v2, tt.l, err, tt.want)
+		}
+	}
+	if G.E != 21 {
+		t.Errorf("failed AddEdge calls changed the size of the graph to %d", G.E)
+	}
+
+	if err := G.DelEdge(4, 0); err != nil || G.E != 20 || G.HasEdge(0, 4) {
+		t.Errorf("DelEdge(4, 0) failed: %v", err)
+	}
+	if err := G.DelEdge(4, 0); !errors.Is(err, ErrMissingEdge) {
+		t.Errorf("DelEdge of a missing edge returned %v", err)
+	}
+	if err := G.DelEdge(4, 20); !errors.Is(err, ErrVertexRange) {
+		t.Errorf("DelEdge out of range returned %v", err)
+	}
+	if err := G.DisconnectVert(5); err != nil || G.E != 16 || G.Degree(5) != 0 {
+		t.Errorf("DisconnectVert(5) failed: %v, %d edges left", err, G.E)
+	}
+	if err := G.AddEdge(0, 4, 1.36); err != nil || G.E != 17 {
+		t.Errorf("AddEdge(0, 4) failed: %v", err)
+	}
+}
+
+// in- and out-degrees of directed graphs
+func TestDirectedDegree(t *testing.T) {
+	G := NewDirectedGraph()
+	G.SetOrder(4)
+	G.AddEdge(0, 1, 1.0)
+	G.AddEdge(1, 0, 2.0)
+	G.AddEdge(2, 1, 1.0)
+	G.AddEdge(1, 3, 1.0)
+	if !G.Directed() || G.E != 4 || G.Weight(1, 0) != 2.0 {
+		t.Fatalf("directed graph set up incorrectly")
+	}
+	if err := G.AddEdge(2, 1, 1.0); !errors.Is(err, ErrDuplicateEdge) {
+		t.Errorf("AddEdge of a duplicate directed edge returned %v", err)
+	}
+	if G.InDegree(1) != 2 || G.OutDegree(1) != 2 || G.InDegree(2) != 0 || G.OutDegree(3) != 0 {
+		t.Errorf("in- or out-degree incorrect")
+	}
+	if pre := G.Predecessors(1); len(pre) != 2 || pre[0] != 0 || pre[1] != 2 {
+		t.Errorf("Predecessors(1) incorrect, got %v", pre)
+	}
+	if suc := G.Successors(1); len(suc) != 2 || suc[0] != 0 || suc[1] != 3 {
+		t.Errorf("Successors(1) incorrect, got %v", suc)
+	}
+	if err := G.DelEdge(3, 1); !errors.Is(err, ErrMissingEdge) {
+		t.Errorf("DelEdge of the reverse edge returned %v", err)
+	}
+	if err := G.DisconnectVert(1); err != nil || G.E != 0 {
+		t.Errorf("DisconnectVert(1) failed: %v, %d edges left", err, G.E)
+	}
+
+	U := NewGraph()
+	U.Example1()
+	if U.Directed() || U.InDegree(5) != 4 || U.OutDegree(5) != 4 || len(U.Predecessors(5)) != 4 {
+		t.Errorf("in- and out-degree of undirected graphs must equal the degree")
+	}
+}
+
+// weight updates and the policies for parallel edges
+func TestParallel(t *testing.T) {
+	G := NewGraph()
+	G.Example2()
+	if err := G.SetWeight(1, 0, 2.5); err != nil || G.Weight(0, 1) != 2.5 || G.Weight(1, 0) != 2.5 {
+		t.Errorf("SetWeight(1, 0) failed: %v", err)
+	}
+	if err := G.SetWeight(0, 2, 2.5); !errors.Is(err, ErrMissingEdge) {
+		t.Errorf("SetWeight of a missing edge returned %v", err)
+	}
+	if err := G.SetWeight(0, 1, math.Inf(1)); !errors.Is(err, ErrWeight) || G.Weight(0, 1) != 2.5 {
+		t.Errorf("SetWeight with an infinite weight returned %v", err)
+	}
+
+	tests := []struct {
+		p    Parallel
+		want float64
+	}{
+		{KeepFirst, 2.0},
+		{KeepMin, 1.0},
+		{Replace, 4.0},
+		{Sum, 7.0},
+	}
+	for _, tt := range tests {
+		G := NewGraph()
+		G.SetOrder(2)
+		G.SetParallel(tt.p)
+		for _, l := range []float64{2.0, 1.0, 4.0} {
+			if err := G.AddEdge(1, 0, l); err != nil {
+				t.Errorf("policy %d: AddEdge failed: %v", tt.p, err)
+			}
+		}
+		if G.E != 1 || G.Weight(0, 1) != tt.want || G.Weight(1, 0) != tt.want {
+			t.Errorf("policy %d: weight incorrect, got %f, want %f", tt.p, G.Weight(0, 1), tt.want)
+		}
+
+		edges := []Edge{{0, 1, 2.0}, {1, 0, 1.0}, {0, 1, 4.0}}
+		C, err := NewCSRParallel(2, false, edges, tt.p)
+		if err != nil || C.E != 1 || C.Weight(0, 1) != tt.want || C.Weight(1, 0) != tt.want {
+			t.Errorf("policy %d: CSR weight incorrect, got %v (%v)", tt.p, C, err)
+		}
+	}
+	if _, err := NewCSRParallel(2, true, []Edge{{0, 1, 2.0}, {1, 0, 1.0}, {0, 1, 4.0}}, RejectParallel); !errors.Is(err, ErrDuplicateEdge) {
+		t.Errorf("NewCSRParallel with RejectParallel returned %v", err)
+	}
+}
+
+// parallel edges of multigraphs and the edge IDs of shortest paths
+func TestMultigraph(t *testing.T) {
+	M := NewMultigraph(4, false)
+	free, _ := M.AddEdge(0, 1, 3.0)
+	toll, _ := M.AddEdge(1, 0, 1.0)
+	last, _ := M.AddEdge(1, 2, 2.0)
+	M.AddEdge(2, 3, 5.0)
+	if _, err := M.AddEdge(2, 2, 1.0); !errors.Is(err, ErrSelfLoop) {
+		t.Errorf("AddEdge of a self-loop returned %v", err)
+	}
+	if M.E != 4 || len(M.EdgesBetween(0, 1)) != 2 || len(M.EdgesBetween(1, 0)) != 2 {
+		t.Fatalf("multigraph set up incorrectly")
+	}
+
+	for _, algo := range []func(Interface, int) ([]float64, []int){Dijkstra, DijkstraFibonacci, BellmanFord} {
+		dist, prev := algo(M, 0)
+		if dist[2] != 3.0 {
+			t.Errorf("distance to node 2 incorrect, got %f, want %f", dist[2], 3.0)
+		}
+		path, _ := GetPathD(0, 2, prev)
+		ids, err := M.PathEdges(path)
+		if err != nil || len(ids) != 2 || ids[0] != toll || ids[1] != last {
+			t.Errorf("edge IDs of the path incorrect, got %v (%v)", ids, err)
+		}
+		if tree := M.TreeEdges(prev); tree[0] != -1 || tree[1] != toll {
+			t.Errorf("edge IDs of the tree incorrect, got %v", tree)
+		}
+	}
+
+	// closing the toll road
+	if err := M.DelEdge(toll); err != nil || M.E != 3 || M.HasEdge(toll) {
+		t.Errorf("DelEdge(%d) failed: %v", toll, err)
+	}
+	if err := M.DelEdge(toll); !errors.Is(err, ErrMissingEdge) {
+		t.Errorf("DelEdge of a deleted edge returned %v", err)
+	}
+	dist, prev := Dijkstra(M, 0)
+	path, _ := GetPathD(0, 3, prev)
+	if ids, _ := M.PathEdges(path); dist[3] != 10.0 || ids[0] != free {
+		t.Errorf("path without the toll road incorrect, got %v", ids)
+	}
+	if _, err := M.PathEdges([]int{0, 3}); !errors.Is(err, ErrMissingEdge) {
+		t.Errorf("PathEdges of an invalid path returned %v", err)
+	}
+}
+
+// adding edges and finding paths by vertex labels
+func TestLabels(t *testing.T) {
+	G := NewGraph()
+	G.SetOrder(4)
+	for v, name := range []string{"ZRH", "BRN", "BSL", "GVA"} {
+		if err := G.SetLabel(v, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := G.SetLabel(3, "BRN"); !errors.Is(err, ErrDuplicateLabel) {
+		t.Errorf("SetLabel with a duplicate label returned %v", err)
+	}
+	if err := G.SetLabel(4, "LUG"); !errors.Is(err, ErrVertexRange) {
+		t.Errorf("SetLabel out of range returned %v", err)
+	}
+	G.AddEdgeLabel("ZRH", "BRN", 1.0)
+	G.AddEdgeLabel("BSL", "BRN", 1.0)
+	G.AddEdgeLabel("GVA", "BRN", 2.0)
+	if err := G.AddEdgeLabel("GVA", "LUG", 2.0); !errors.Is(err, ErrUnknownLabel) {
+		t.Errorf("AddEdgeLabel with an unknown label returned %v", err)
+	}
+
+	start, _ := G.Vertex("GVA")
+	end, _ := G.Vertex("ZRH")
+	dist, prev := Dijkstra(G, start)
+	path, _ := GetPathD(start, end, prev)
+	names := G.LabelPath(path)
+	if dist[end] != 3.0 || len(names) != 3 || names[0] != "GVA" || names[1] != "BRN" || names[2] != "ZRH" {
+		t.Errorf("path from GVA to ZRH incorrect, got %v", names)
+	}
+	if back, err := G.Labels().Indices(names); err != nil || back[2] != end {
+		t.Errorf("Indices(%v) incorrect, got %v (%v)", names, back, err)
+	}
+
+	// relabelling a vertex frees its old label
+	G.SetLabel(3, "GE")
+	if _, err := G.Vertex("GVA"); !errors.Is(err, ErrUnknownLabel) || G.Label(3) != "GE" || G.Labels().Len() != 4 {
+		t.Errorf("relabelling vertex 3 failed")
+	}
+
+	// reading the labels of a graph without labels doesn't change it
+	U := NewGraph()
+	U.Example1()
+	if _, err := U.Vertex("GVA"); !errors.Is(err, ErrUnknownLabel) || U.Label(0) != "" || U.LabelPath([]int{0, 1})[1] != "" || U.Labels() != nil {
+		t.Errorf("labels of a graph without labels incorrect")
+	}
+}
+
+// adding and deleting vertices without rebuilding the graph
+func TestVertices(t *testing.T) {
+	G := NewGraph()
+	G.Example1()
+	G.SetLabel(15, "end")
+	if v := G.AddVertex(); v != 16 || G.V != 17 || G.E != 21 || !G.HasEdge(14, 15) {
+		t.Fatalf("AddVertex failed, got vertex %d", v)
+	}
+	if v := G.AddVertices(2); v != 17 || G.V != 19 || G.Degree(18) != 0 {
+		t.Fatalf("AddVertices failed, got vertex %d", v)
+	}
+	G.AddEdge(15, 16, 1.0)
+	G.AddEdge(16, 18, 1.0)
+	G.SetLabel(18, "new")
+
+	if err := G.DelVertex(5); err != nil || G.E != 19 || !G.Deleted(5) {
+		t.Fatalf("DelVertex(5) failed: %v", err)
+	}
+	if err := G.AddEdge(5, 6, 1.0); !errors.Is(err, ErrVertexRange) {
+		t.Errorf("AddEdge to a deleted vertex returned %v", err)
+	}
+	if err := G.DelVertex(5); !errors.Is(err, ErrVertexRange) {
+		t.Errorf("DelVertex of a deleted vertex returned %v", err)
+	}
+	G.DelVertex(17)
+	dist, _ := Dijkstra(G, 0)
+
+	remap := G.Compact()
+	if G.V != 17 || G.E != 19 || remap[5] != -1 || remap[17] != -1 || remap[4] != 4 || remap[6] != 5 || remap[18] != 16 {
+		t.Fatalf("Compact failed, got %d vertices, remapping %v", G.V, remap)
+	}
+	if v, _ := G.Vertex("new"); v != 16 || G.Label(14) != "end" {
+		t.Errorf("labels were not remapped")
+	}
+	distC, _ := Dijkstra(G, 0)
+	for v, w := range remap {
+		if w != -1 && dist[v] != distC[w] {
+			t.Errorf("distance to node %d changed by Compact, got %f, want %f", v, distC[w], dist[v])
+		}
+	}
+}
+
+// iterating over the edges of a graph
+func TestEdges(t *testing.T) {
+	G := NewGraph()
+	G.Example2()
+	C := CSRFromGraph(G)
+	n := 0
+	for e := range G.Edges() {
+		if e.From > e.To || e.Weight != G.Weight(e.From, e.To) || !C.HasEdge(e.To, e.From) {
+			t.Errorf("edge %v incorrect", e)
+		}
+		n++
+	}
+	if n != G.E {
+		t.Errorf("number of edges incorrect, got %d, want %d", n, G.E)
+	}
+	var edges []Edge
+	for e := range C.Edges() {
+		edges = append(edges, e)
+	}
+	if len(edges) != G.E || edges[0] != (Edge{0, 1, 1.85}) {
+		t.Errorf("CSR edges incorrect, got %v", edges)
+	}
+
+	// both directions of the edges are seen by the algorithms
+	n = 0
+	for e := range Arcs[float64](G) {
+		if !G.HasEdge(e.From, e.To) {
+			t.Errorf("arc %v incorrect", e)
+		}
+		if n++; n == 30 {
+			break
+		}
+	}
+	if n != 30 {
+		t.Errorf("stopping the iteration failed, got %d arcs", n)
+	}
+	deg := 0
+	for v, w := range G.Adjacent(5) {
+		if w != G.Weight(5, v) {
+			t.Errorf("weight of edge (5,%d) incorrect, got %f", v, w)
+		}
+		deg++
+	}
+	if deg != G.Degree(5) {
+		t.Errorf("number of neighbours of node 5 incorrect, got %d", deg)
+	}
+
+	// the neighbour lists are kept in sync with the matrices
+	G.DelEdge(5, 3)
+	G.AddEdge(5, 0, 1.0)
+	if nei, deg := G.Neighbours(5); deg != 4 || nei[0] != 0 || nei[1] != 6 {
+		t.Errorf("neighbours of node 5 incorrect, got %v", nei)
+	}
+}
+
+// extracting subgraphs with renumbered vertices
+func TestSubgraph(t *testing.T) {
+	G := NewGraph()
+	G.Example2()
+	G.SetLabel(14, "hub")
+	S, remap, err := G.Induced([]int{14, 6, 12, 13, 11})
+	if err != nil || S.V != 5 || S.E != 5 || remap[14] != 0 || remap[11] != 4 || remap[0] != -1 {
+		t.Fatalf("induced subgraph incorrect, got %d vertices, %d edges, remapping %v (%v)", S.V, S.E, remap, err)
+	}
+	if S.Weight(0, 1) != G.Weight(14, 6) || S.Label(0) != "hub" || S.HasEdge(1, 2) {
+		t.Errorf("edges or labels of the induced subgraph incorrect")
+	}
+	S, _, _ = G.Subgraph([]int{14, 6, 12, 13, 11}, func(e Edge) bool { return e.Weight < 1.5 })
+	if S.E != 2 || !S.HasEdge(2, 4) || !S.HasEdge(0, 2) {
+		t.Errorf("filtered subgraph incorrect, got %d edges", S.E)
+	}
+	if _, _, err := G.Induced([]int{1, 2, 1}); err == nil {
+		t.Errorf("Induced with a duplicate vertex did not fail")
+	}
+	if _, _, err := G.Induced([]int{1, 20}); !errors.Is(err, ErrVertexRange) {
+		t.Errorf("Induced with a vertex out of range returned %v", err)
+	}
+}
+
+// reading and writing edge lists, the fixtures are kept in testdata
+func TestEdgeList(t *testing.T) {
+	f, err := os.Open("testdata/example2.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	G, err := ReadEdgeList(f)
+	E := NewGraph()
+	E.Example2()
+	if err != nil || G.V != E.V || G.E != E.E || G.Directed() {
+		t.Fatalf("reading example 2 incorrect, got %d vertices, %d edges (%v)", G.V, G.E, err)
+	}
+	for e := range E.Edges() {
+		if G.Weight(e.From, e.To) != e.Weight {
+			t.Errorf("weight of edge %v incorrect, got %v", e, G.Weight(e.From, e.To))
+		}
+	}
+
+	// writing and reading again gives the same graph
+	D := directedExample2()
+	var buf bytes.Buffer
+	if err := WriteEdgeList(&buf, D); err != nil {
+		t.Fatal(err)
+	}
+	C, err := ReadEdgeListCSR(&buf)
+	if err != nil || C.V != D.V || C.E != D.E || !C.Directed() {
+		t.Fatalf("round trip incorrect, got %d vertices, %d edges (%v)", C.V, C.E, err)
+	}
+	for e := range D.Edges() {
+		if C.Weight(e.From, e.To) != e.Weight {
+			t.Errorf("weight of edge %v incorrect after round trip", e)
+		}
+	}
+
+	// without header, comma-separated, default weight
+	G, err = ReadEdgeList(strings.NewReader("0,1\n\n# comment\n1, 3, 2.5\n"))
+	if err != nil || G.V != 4 || G.E != 2 || G.Weight(0, 1) != 1 || G.Weight(3, 1) != 2.5 {
+		t.Errorf("edge list without header incorrect (%v)", err)
+	}
+
+	var tests = []struct {
+		input string
+		line  int
+		err   error
+	}{
+		{"3 directed\n0 1 1\n0 3 1\n", 3, ErrVertexRange},
+		{"3\n0 1 1\n# comment\n1 0 2\n", 4, ErrDuplicateEdge},
+		{"0 1 x\n", 1, ErrWeight},
+		{"0 1 NaN\n", 1, ErrWeight},
+		{"0 1\n1 1\n", 2, ErrSelfLoop},
+		{"0 1 2 3\n", 1, nil},
+		{"-3 undirected\n", 1, nil},
+		{"0 a\n", 1, nil},
+		{"100000 directed\n0 1\n", 1, ErrOrder},
+		{"0 1\n1 999999\n2 3\n", 2, ErrOrder},
+		{"0 9223372036854775807\n", 1, nil},
+		{"0 " + strconv.Itoa(MaxCSROrder) + "\n", 1, ErrOrder},
+	}
+	for _, tt := range tests {
+		_, err := ReadEdgeList(strings.NewReader(tt.input))
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Line != tt.line || (tt.err != nil && !errors.Is(err, tt.err)) {
+			t.Errorf("ReadEdgeList(%q) incorrect, got %v, want line %d: %v", tt.input, err, tt.line, tt.err)
+		}
+	}
+
+	// the CSR reader has its own limit, but rejects self-loops as well
+	tests = []struct {
+		input string
+		line  int
+		err   error
+	}{
+		{"3 directed\n0 1 1\n0 3 1\n", 3, ErrVertexRange},
+		{"0 1\n1 1\n", 2, ErrSelfLoop},
+		{strconv.Itoa(MaxCSROrder+1) + " directed\n", 1, ErrOrder},
+	}
+	for _, tt := range tests {
+		_, err := ReadEdgeListCSR(strings.NewReader(tt.input))
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Line != tt.line || !errors.Is(err, tt.err) {
+			t.Errorf("ReadEdgeListCSR(%q) incorrect, got %v, want line %d: %v", tt.input, err, tt.line, tt.err)
+		}
+	}
+	if C, err := ReadEdgeListCSR(strings.NewReader("100000\n0 1\n")); err != nil || C.V != 100000 {
+		t.Errorf("ReadEdgeListCSR with many vertices incorrect (%v)", err)
+	}
+}
+
+// exporting graphs to Graphviz with highlighted paths
+func TestDOT(t *testing.T) {
+	G := NewGraph()
+	G.Example2()
+	G.SetLabel(0, `depot "A"`)
+	_, prev := Dijkstra(G, 0)
+	path, _ := GetPathD(0, 13, prev)
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, G, &DOTOptions{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"graph \"G\" {\n",
+		"\t0 [label=\"depot \\\"A\\\"\", color=\"red\"];\n",
+		"\t1;\n",
+		"\t0 -- 1 [label=\"1.85\"];\n",
+		"\t0 -- 4 [label=\"1.36\", color=\"red\", penwidth=2];\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output does not contain %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "penwidth"); n != len(path)-1 {
+		t.Errorf("number of highlighted edges incorrect, got %d, want %d", n, len(path)-1)
+	}
+
+	// the whole shortest-path tree of a directed graph
+	D := directedExample2()
+	_, prev = Dijkstra(D, 0)
+	buf.Reset()
+	WriteDOT(&buf, D, &DOTOptions{Name: "tree", Tree: prev, Color: "blue"})
+	out = buf.String()
+	if !strings.HasPrefix(out, "digraph \"tree\" {\n") || !strings.Contains(out, "\t0 -> 1 [label=\"1.85\", color=\"blue\", penwidth=2];\n") {
+		t.Errorf("DOT output of the directed graph incorrect:\n%s", out)
+	}
+	if n := strings.Count(out, "penwidth"); n != D.V-1 {
+		t.Errorf("number of highlighted tree edges incorrect, got %d, want %d", n, D.V-1)
+	}
+}
+
+// an attributed graph with a parallel edge for the exchange formats
+func exchangeExample() *Attributed {
+	A := NewAttributed(3, true, "length", "time")
+	A.AddEdge(0, 1, 2.5, 3)
+	A.AddEdge(0, 1, 1.0/3, 7)
+	A.AddEdge(1, 2, 1e-9, 0)
+	A.SetLabel(0, "A")
+	A.SetLabel(2, "depot <C>")
+	return A
+}
+
+// GraphML and node-link JSON must round-trip
+func TestExchange(t *testing.T) {
+	G := NewGraph()
+	G.Example2()
+	G.SetLabel(3, "A & <B>")
+	G.SetLabel(14, `"hub"`)
+	D := directedExample2()
+	A := exchangeExample()
+	formats := []struct {
+		name            string
+		write           func(*bytes.Buffer, *Graph) error
+		read            func(*bytes.Buffer) (*Graph, error)
+		writeAttributed func(*bytes.Buffer, *Attributed) error
+		readAttributed  func(*bytes.Buffer) (*Attributed, error)
+	}{
+		{"GraphML",
+			func(b *bytes.Buffer, G *Graph) error { return WriteGraphML(b, G) },
+			func(b *bytes.Buffer) (*Graph, error) { return ReadGraphML(b) },
+			func(b *bytes.Buffer, A *Attributed) error { return WriteAttributedGraphML(b, A) },
+			func(b *bytes.Buffer) (*Attributed, error) { return ReadAttributedGraphML(b) }},
+		{"JSON",
+			func(b *bytes.Buffer, G *Graph) error { return WriteJSON(b, G) },
+			func(b *bytes.Buffer) (*Graph, error) { return ReadJSON(b) },
+			func(b *bytes.Buffer, A *Attributed) error { return WriteAttributedJSON(b, A) },
+			func(b *bytes.Buffer) (*Attributed, error) { return ReadAttributedJSON(b) }},
+	}
+	for _, f := range formats {
+		for _, want := range []*Graph{G, D} {
+			var buf bytes.Buffer
+			if err := f.write(&buf, want); err != nil {
+				t.Fatal(err)
+			}
+			got, err := f.read(&buf)
+			if err != nil || got.V != want.V || got.E != want.E || got.Directed() != want.Directed() {
+				t.Fatalf("%s round trip incorrect (%v)", f.name, err)
+			}
+			for e := range want.Edges() {
+				if !got.HasEdge(e.From, e.To) || got.Weight(e.From, e.To) != e.Weight {
+					t.Errorf("%s: edge %v incorrect after round trip", f.name, e)
+				}
+			}
+			for v := 0; v < want.V; v++ {
+				if got.Label(v) != want.Label(v) {
+					t.Errorf("%s: label of vertex %d incorrect, got %q, want %q", f.name, v, got.Label(v), want.Label(v))
+				}
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := f.writeAttributed(&buf, A); err != nil {
+			t.Fatal(err)
+		}
+		B, err := f.readAttributed(&buf)
+		if err != nil || B.Size() != A.Size() || !B.Directed() || !slices.Equal(B.Names(), A.Names()) {
+			t.Fatalf("%s round trip of the attributes incorrect (%v)", f.name, err)
+		}
+		for id, e := range A.EdgeIDs() {
+			if B.Edge(id) != e || !slices.Equal(B.Attrs(id), A.Attrs(id)) {
+				t.Errorf("%s: edge %d incorrect after round trip, got %v", f.name, id, B.Attrs(id))
+			}
+		}
+		if !sameLabels(A.Order(), A.Label, B.Label) {
+			t.Errorf("%s: labels of the attributed graph incorrect after round trip", f.name)
+		}
+	}
+}
+
+// do two graphs with n vertices have the same labels?
+func sameLabels(n int, a, b func(v int) string) bool {
+	for v := 0; v < n; v++ {
+		if a(v) != b(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// files written by other tools
+func TestExchangeImport(t *testing.T) {
+	graphml := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="label" attr.type="string"/>
+  <key id="d1" for="edge" attr.name="weight" attr.type="double"><default>2</default></key>
+  <key id="d2" for="edge" attr.name="name" attr.type="string"/>
+  <graph edgedefault="directed">
+    <node id="Berlin"/>
+    <node id="n1"><data key="d0">Hamburg</data></node>
+    <edge source="Berlin" target="n1"><data key="d1">289</data><data key="d2">A24</data></edge>
+    <node id="n2"/>
+    <edge source="n1" target="n2"/>
+  </graph>
+</graphml>`
+	G, err := ReadGraphML(strings.NewReader(graphml))
+	if err != nil || G.V != 3 || !G.Directed() || G.Weight(0, 1) != 289 || G.Weight(1, 2) != 2 {
+		t.Fatalf("reading GraphML incorrect (%v)", err)
+	}
+	if G.Label(0) != "Berlin" || G.Label(1) != "Hamburg" || G.Label(2) != "" {
+		t.Errorf("labels incorrect, got %v", G.LabelPath([]int{0, 1, 2}))
+	}
+	A, err := ReadAttributedGraphML(strings.NewReader(graphml))
+	if err != nil || !sameLabels(3, A.Label, G.Label) {
+		t.Errorf("labels of the attributed graph incorrect (%v)", err)
+	}
+
+	nx := `{"directed": false, "multigraph": false, "graph": {"name": "test"},
+	  "nodes": [{"id": "a", "color": "red"}, {"id": 7}, {"id": 2}],
+	  "edges": [{"source": "a", "target": 7, "weight": 0.5}, {"source": 7, "target": 2, "capacity": 3}]}`
+	G, err = ReadJSON(strings.NewReader(nx))
+	if err != nil || G.V != 3 || G.Directed() || G.Weight(1, 0) != 0.5 || G.Weight(1, 2) != 1 {
+		t.Fatalf("reading JSON incorrect (%v)", err)
+	}
+	if G.Label(0) != "a" || G.Label(1) != "7" || G.Label(2) != "" {
+		t.Errorf("labels incorrect, got %v", G.LabelPath([]int{0, 1, 2}))
+	}
+	if _, err := ReadAttributedJSON(strings.NewReader(nx)); !errors.Is(err, ErrWeight) {
+		t.Errorf("missing attributes were not reported, got %v", err)
+	}
+
+	var tests = []struct {
+		input string
+		err   error
+	}{
+		{`<graphml><graph><node id="a"/><edge source="a" target="b"/></graph></graphml>`, ErrVertexRange},
+		{`<graphml><graph><node id="a"/><node id="b"/><edge source="a" target="b"/><edge source="b" target="a"/></graph></graphml>`, ErrDuplicateEdge},
+		{`<graphml><key id="w" for="edge" attr.name="weight" attr.type="double"/><graph><node id="a"/><node id="b"/><edge source="a" target="b"><data key="w">x</data></edge></graph></graphml>`, ErrWeight},
+		{`<graphml></graphml>`, nil},
+		{`<graphml><graph><node id="a"/><node id="a"/></graph></graphml>`, nil},
+		{`{"nodes": [{"id": 0}], "links": [{"source": 0, "target": 0}]}`, ErrSelfLoop},
+		{`{"nodes": [{"id": 0}, {"id": 1}], "links": [{"source": 0, "target": 1, "weight": "heavy"}]}`, ErrWeight},
+		{`{"nodes": [{"label": "x"}]}`, nil},
+		{`{"nodes": [`, nil},
+	}
+	for _, tt := range tests {
+		var err error
+		if strings.HasPrefix(tt.input, "<") {
+			_, err = ReadGraphML(strings.NewReader(tt.input))
+		} else {
+			_, err = ReadJSON(strings.NewReader(tt.input))
+		}
+		if err == nil || (tt.err != nil && !errors.Is(err, tt.err)) {
+			t.Errorf("reading %q incorrect, got %v, want %v", tt.input, err, tt.err)
+		}
+	}
+
+	// the number of nodes is limited for a Graph, but not for a multigraph
+	defer func(limit int) { MaxOrder = limit }(MaxOrder)
+	MaxOrder = 1
+	pair := `{"nodes": [{"id": 0}, {"id": 1}], "links": []}`
+	if _, err := ReadJSON(strings.NewReader(pair)); !errors.Is(err, ErrOrder) {
+		t.Errorf("reading too many nodes incorrect, got %v, want %v", err, ErrOrder)
+	}
+	if _, err := ReadAttributedJSON(strings.NewReader(pair)); err != nil {
+		t.Errorf("reading an attributed graph incorrect, got %v", err)
+	}
+}
+
+// decode arbitrary input, a decoded graph must survive a round trip
+func FuzzReadGraphML(f *testing.F) {
+	G := NewGraph()
+	G.Example2()
+	G.SetLabel(0, "start")
+	var buf bytes.Buffer
+	WriteGraphML(&buf, G)
+	f.Add(buf.String())
+	buf.Reset()
+	WriteAttributedGraphML(&buf, exchangeExample())
+	f.Add(buf.String())
+	f.Add(`<graphml><graph edgedefault="directed"><node id="a"/><node id="b"/><edge source="a" target="b"/></graph></graphml>`)
+	f.Fuzz(func(t *testing.T, input string) {
+		if len(input) > 1<<14 {
+			return // the adjacency matrices grow quadratically
+		}
+		G, err := ReadGraphML(strings.NewReader(input))
+		if err == nil {
+			var buf bytes.Buffer
+			if err := WriteGraphML(&buf, G); err != nil {
+				t.Fatal(err)
+			}
+			H, err := ReadGraphML(&buf)
+			if err != nil || H.V != G.V || H.E != G.E || !sameLabels(G.V, G.Label, H.Label) {
+				t.Fatalf("round trip of %q failed (%v)", input, err)
+			}
+		}
+		if A, err := ReadAttributedGraphML(strings.NewReader(input)); err == nil {
+			var buf bytes.Buffer
+			if err := WriteAttributedGraphML(&buf, A); err != nil {
+				t.Fatal(err)
+			}
+			if B, err := ReadAttributedGraphML(&buf); err != nil || B.Size() != A.Size() || !sameLabels(A.Order(), A.Label, B.Label) {
+				t.Fatalf("round trip of the attributes of %q failed (%v)", input, err)
+			}
+		}
+	})
+}
+
+// decode arbitrary input, a decoded graph must survive a round trip
+func FuzzReadJSON(f *testing.F) {
+	G := NewGraph()
+	G.Example2()
+	G.SetLabel(0, "start")
+	var buf bytes.Buffer
+	WriteJSON(&buf, G)
+	f.Add(buf.String())
+	buf.Reset()
+	WriteAttributedJSON(&buf, exchangeExample())
+	f.Add(buf.String())
+	f.Add(`{"directed": true, "nodes": [{"id": "a"}, {"id": 1}], "edges": [{"source": "a", "target": 1, "weight": 2}]}`)
+	f.Fuzz(func(t *testing.T, input string) {
+		if len(input) > 1<<14 {
+			return // the adjacency matrices grow quadratically
+		}
+		G, err := ReadJSON(strings.NewReader(input))
+		if err == nil {
+			var buf bytes.Buffer
+			if err := WriteJSON(&buf, G); err != nil {
+				t.Fatal(err)
+			}
+			H, err := ReadJSON(&buf)
+			if err != nil || H.V != G.V || H.E != G.E || !sameLabels(G.V, G.Label, H.Label) {
+				t.Fatalf("round trip of %q failed (%v)", input, err)
+			}
+		}
+		if A, err := ReadAttributedJSON(strings.NewReader(input)); err == nil {
+			var buf bytes.Buffer
+			if err := WriteAttributedJSON(&buf, A); err != nil {
+				t.Fatal(err)
+			}
+			if B, err := ReadAttributedJSON(&buf); err != nil || B.Size() != A.Size() || !sameLabels(A.Order(), A.Label, B.Label) {
+				t.Fatalf("round trip of the attributes of %q failed (%v)", input, err)
+			}
+		}
+	})
+}
+
+// dense weight matrices and Matrix Market files
+func TestMatrix(t *testing.T) {
+	G := NewGraph()
+	G.Example2()
+	D := directedExample2()
+	formats := []struct {
+		name  string
+		write func(io.Writer, *Graph) error
+		read  func(io.Reader) (*Graph, error)
+	}{
+		{"matrix", WriteMatrix, ReadMatrix},
+		{"Matrix Market", WriteMatrixMarket, ReadMatrixMarket},
+		{"Matrix Market array", WriteMatrixMarketArray, ReadMatrixMarket},
+	}
+	for _, f := range formats {
+		for _, want := range []*Graph{G, D} {
+			var buf bytes.Buffer
+			if err := f.write(&buf, want); err != nil {
+				t.Fatal(err)
+			}
+			got, err := f.read(&buf)
+			if err != nil || got.V != want.V || got.E != want.E || got.Directed() != want.Directed() {
+				t.Fatalf("%s round trip incorrect (%v)", f.name, err)
+			}
+			for i := range want.emat {
+				if !slices.Equal(got.emat[i], want.emat[i]) || !slices.Equal(got.nmat[i], want.nmat[i]) {
+					t.Errorf("%s: row %d incorrect after round trip, got %v", f.name, i, got.emat[i])
+				}
+			}
+		}
+	}
+
+	// the dense layout is the one of the matrices
+	var buf bytes.Buffer
+	S, _, _ := G.Induced([]int{0, 1, 2})
+	WriteMatrix(&buf, S)
+	if want := "# weight matrix, 0 = no edge\n3 undirected\n0 1.85 0\n1.85 0 1.51\n0 1.51 0\n"; buf.String() != want {
+		t.Errorf("dense matrix incorrect, got %q, want %q", buf.String(), want)
+	}
+	buf.Reset()
+	WriteMatrixMarket(&buf, S)
+	if want := "%%MatrixMarket matrix coordinate real symmetric\n3 3 2\n2 1 1.85\n3 2 1.51\n"; buf.String() != want {
+		t.Errorf("Matrix Market file incorrect, got %q, want %q", buf.String(), want)
+	}
+
+	mm := `%%MatrixMarket matrix coordinate pattern general
+% a directed cycle
+3 3 3
+1 2
+2 3
+
+3 1
+`
+	C, err := ReadMatrixMarket(strings.NewReader(mm))
+	if err != nil || !C.Directed() || C.E != 3 || C.Weight(2, 0) != 1 || C.HasEdge(1, 0) {
+		t.Errorf("reading a pattern matrix incorrect (%v)", err)
+	}
+	C, err = ReadMatrixMarket(strings.NewReader("%%MatrixMarket matrix array integer general\n2 2\n0\n3\n4\n0\n"))
+	if err != nil || C.E != 2 || C.Weight(1, 0) != 3 || C.Weight(0, 1) != 4 {
+		t.Errorf("reading a general array incorrect (%v)", err)
+	}
+
+	S.AddEdge(0, 2, 0)
+	if err := WriteMatrix(io.Discard, S); !errors.Is(err, ErrWeight) {
+		t.Errorf("writing a weight of 0 did not fail, got %v", err)
+	}
+
+	var tests = []struct {
+		input string
+		line  int
+		err   error
+	}{
+		{"", 1, nil},
+		{"# comment\n2 undirected\n0 1\n2 0\n", 4, nil},
+		{"2\n0 1\n1 0 0\n", 3, nil},
+		{"2\n0 1\n1\n", 4, nil},
+		{"2 directed\n1 1\n1 0\n", 2, ErrSelfLoop},
+		{"2\n0 x\n1 0\n", 2, ErrWeight},
+		{"1\n0\n0\n", 3, nil},
+		{"%%MatrixMarket matrix coordinate complex general\n", 1, nil},
+		{"%%MatrixMarket matrix coordinate real general\n2 3 0\n", 2, nil},
+		{"%%MatrixMarket matrix coordinate real symmetric\n%\n2 2 2\n2 1 1.5\n1 2 1.5\n", 5, ErrDuplicateEdge},
+		{"%%MatrixMarket matrix coordinate real general\n2 2 1\n1 3 1.5\n", 3, ErrVertexRange},
+		{"%%MatrixMarket matrix coordinate real general\n2 2 2\n1 2 1.5\n", 4, nil},
+		{"%%MatrixMarket matrix array real general\n2 2\n0\n1\n", 5, nil},
+		{"%%MatrixMarket matrix coordinate real general\n3000000 3000000 0\n", 2, ErrOrder},
+		{"# comment\n3000000 directed\n", 2, ErrOrder},
+	}
+	for _, tt := range tests {
+		var err error
+		if strings.HasPrefix(tt.input, "%") {
+			_, err = ReadMatrixMarket(strings.NewReader(tt.input))
+		} else {
+			_, err = ReadMatrix(strings.NewReader(tt.input))
+		}
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Line != tt.line || (tt.err != nil && !errors.Is(err, tt.err)) {
+			t.Errorf("reading %q incorrect, got %v, want line %d: %v", tt.input, err, tt.line, tt.err)
+		}
+	}
+}
+
+// binary snapshots of CSR graphs
+func TestSnapshot(t *testing.T) {
+	C := CSRFromGraph(directedExample2())
+	coords := make([]Point, C.V)
+	for v := range coords {
+		coords[v] = Point{float64(v), -float64(v) / 2}
+	}
+	name := t.TempDir() + "/example.snap"
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteSnapshot(f, C, coords); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	S, err := OpenSnapshot(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := S.Verify(); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+	if S.V != C.V || S.E != C.E || !S.Directed() || !slices.Equal(S.Targets, C.Targets) || !slices.Equal(S.Coords, coords) {
+		t.Errorf("snapshot incorrect, got %d vertices, %d edges", S.V, S.E)
+	}
+	want, _ := Dijkstra(C, 0)
+	if got, _ := Dijkstra(S, 0); !slices.Equal(got, want) {
+		t.Errorf("distances on the snapshot incorrect, got %v, want %v", got, want)
+	}
+	if err := S.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+
+	// undirected without coordinates, read into memory
+	var buf bytes.Buffer
+	G := NewGraph()
+	G.Example2()
+	WriteSnapshot(&buf, CSRFromGraph(G), nil)
+	data := buf.Bytes()
+	S, err = ReadSnapshot(bytes.NewReader(data))
+	if err != nil || S.Directed() || S.E != G.E || S.Coords != nil || S.Weight(14, 6) != G.Weight(14, 6) {
+		t.Errorf("reading the snapshot incorrect (%v)", err)
+	}
+
+	// the arrays are copied on other machines
+	native := nativeSnapshot
+	nativeSnapshot = false
+	T, err := ReadSnapshot(bytes.NewReader(data))
+	nativeSnapshot = native
+	if err != nil || !slices.Equal(T.Offsets, S.Offsets) || !slices.Equal(T.Weights, S.Weights) {
+		t.Errorf("reading the snapshot without zero-copy incorrect (%v)", err)
+	}
+
+	// damaged snapshots
+	corrupt := func(pos int) []byte {
+		d := slices.Clone(data)
+		d[pos] ^= 1
+		return d
+	}
+	// modified snapshots with valid checksums
+	craft := func(modify func(d []byte)) []byte {
+		d := slices.Clone(data)
+		modify(d)
+		binary.LittleEndian.PutUint32(d[40:], crc32.Checksum(d[snapshotHeader:], castagnoli))
+		binary.LittleEndian.PutUint32(d[44:], crc32.Checksum(d[:44], castagnoli))
+		return d
+	}
+	unsorted := craft(func(d []byte) {
+		// swap the first two targets of the row of a vertex with two neighbours
+		v := 0
+		for S.Degree(v) < 2 {
+			v++
+		}
+		pos := snapshotHeader + 8*(S.V+1) + 8*S.Offsets[v]
+		a, b := slices.Clone(d[pos:pos+8]), slices.Clone(d[pos+8:pos+16])
+		copy(d[pos:], b)
+		copy(d[pos+8:], a)
+	})
+	edges := craft(func(d []byte) {
+		binary.LittleEndian.PutUint64(d[24:], uint64(S.E+1))
+	})
+	var tests = []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"unsorted", unsorted, ErrSnapshot},
+		{"edges", edges, ErrSnapshot},
+		{"data", corrupt(len(data) - 1), ErrChecksum},
+		{"header", corrupt(20), ErrChecksum},
+		{"magic", corrupt(0), ErrSnapshot},
+		{"truncated", data[:len(data)-8], ErrSnapshot},
+		{"empty", nil, ErrSnapshot},
+	}
+	for _, tt := range tests {
+		if _, err := ReadSnapshot(bytes.NewReader(tt.data)); !errors.Is(err, tt.err) {
+			t.Errorf("%s: ReadSnapshot returned %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+// importing roads from OpenStreetMap
+func TestOSM(t *testing.T) {
+	osm := `<?xml version="1.0" encoding="UTF-8"?>
+<osm version="0.6">
+  <node id="1" lat="0" lon="0"/>
+  <node id="2" lat="0" lon="0.001"/>
+  <node id="3" lat="0" lon="0.002"/>
+  <node id="4" lat="0.001" lon="0.001"/>
+  <node id="5" lat="-0.001" lon="0.001"><tag k="highway" v="traffic_signals"/></node>
+  <node id="6" lat="0" lon="0.003"/>
+  <node id="7" lat="0" lon="0.004"/>
+  <way id="10"><nd ref="1"/><nd ref="2"/><nd ref="3"/><nd ref="6"/><tag k="highway" v="residential"/></way>
+  <way id="11"><nd ref="4"/><nd ref="2"/><nd ref="5"/><tag k="highway" v="primary"/><tag k="oneway" v="yes"/></way>
+  <way id="12"><nd ref="6"/><nd ref="7"/><tag k="highway" v="footway"/></way>
+  <way id="13"><nd ref="5"/><nd ref="6"/><tag k="highway" v="residential"/><tag k="oneway" v="-1"/></way>
+  <way id="14"><nd ref="6"/><nd ref="99"/><nd ref="1"/><tag k="highway" v="tertiary"/></way>
+  <relation id="20"><member type="way" ref="10" role=""/></relation>
+</osm>`
+	N, err := ReadOSM(strings.NewReader(osm), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(N.IDs, []int64{1, 2, 6, 4, 5}) || N.Coords[2] != (Point{0.003, 0}) {
+		t.Fatalf("vertices incorrect, got %v", N.IDs)
+	}
+	G, err := N.Graph()
+	if err != nil || !G.Directed() || G.E != 7 {
+		t.Fatalf("graph incorrect (%v)", err)
+	}
+	const degree = 2 * math.Pi * EarthRadius / 360 // length of a degree at the equator
+	if math.Abs(G.Weight(0, 1)-degree/1000) > 1e-6 || math.Abs(G.Weight(2, 1)-degree/500) > 1e-6 {
+		t.Errorf("lengths incorrect, got %f and %f", G.Weight(0, 1), G.Weight(2, 1))
+	}
+	if !G.HasEdge(3, 1) || G.HasEdge(1, 3) || !G.HasEdge(2, 4) || G.HasEdge(4, 2) || G.HasEdge(2, 0) {
+		t.Errorf("oneway roads incorrect")
+	}
+	if v, err := G.Vertex("6"); err != nil || v != 2 {
+		t.Errorf("label of OSM node 6 incorrect, got %d (%v)", v, err)
+	}
+	if C, err := N.CSR(); err != nil || C.E != 7 || C.Weight(1, 2) != G.Weight(1, 2) {
+		t.Errorf("CSR graph incorrect (%v)", err)
+	}
+
+	N, _ = ReadOSM(strings.NewReader(osm), &OSMOptions{Highways: []string{"residential", "footway"}, IgnoreOneway: true})
+	// node 2 is no intersection anymore
+	if G, _ := N.Graph(); G.Directed() || G.V != 4 || G.E != 3 || !G.HasEdge(1, 3) || math.Abs(G.Weight(0, 1)-degree*0.003) > 1e-6 {
+		t.Errorf("graph without oneway roads incorrect, got %d vertices, %d edges", G.V, G.E)
+	}
+
+	// the graph is limited to MaxOrder vertices
+	defer func(limit int) { MaxOrder = limit }(MaxOrder)
+	MaxOrder = 3
+	if _, err := N.Graph(); !errors.Is(err, ErrOrder) {
+		t.Errorf("graph above MaxOrder was not rejected, got %v", err)
+	}
+
+	for _, lat := range []string{"x", "NaN", "+Inf", "90.5"} {
+		_, err = ReadOSM(strings.NewReader("<osm>\n<node id=\"1\" lat=\""+lat+"\" lon=\"0\"/>\n</osm>"), nil)
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Line != 2 {
+			t.Errorf("invalid latitude %s was not reported, got %v", lat, err)
+		}
+	}
+	if _, err = ReadOSM(strings.NewReader(`<osm><node id="1" lat="0" lon="-180.1"/></osm>`), nil); err == nil {
+		t.Errorf("invalid longitude was not reported")
+	}
+	if _, err = ReadOSM(strings.NewReader("<osm><way></osm>"), nil); err == nil {
+		t.Errorf("invalid XML was not reported")
+	}
+}
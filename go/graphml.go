@@ -0,0 +1,225 @@
+/* This file contains routines to read and write graphs in GraphML,
+the XML format used by Gephi, yEd or NetworkX. The weights (and other
+edge attributes) are stored as <data> elements of the edges, the vertex
+labels as <data> elements with the key "label" of the nodes.
+See http://graphml.graphdrawing.org */
+
+package shortestpath
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type graphML struct {
+	XMLName xml.Name       `xml:"graphml"`
+	Xmlns   string         `xml:"xmlns,attr,omitempty"`
+	Keys    []graphMLKey   `xml:"key"`
+	Graphs  []graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID      string  `xml:"id,attr"`
+	For     string  `xml:"for,attr"`
+	Name    string  `xml:"attr.name,attr"`
+	Type    string  `xml:"attr.type,attr"`
+	Default *string `xml:"default"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr,omitempty"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// the key of the vertex labels
+const labelAttr = "label"
+
+// WriteGraphML writes a graph in GraphML format,
+// with the weights and vertex labels
+func WriteGraphML(w io.Writer, G *Graph) error {
+	return docFromGraph(G).writeGraphML(w)
+}
+
+// WriteAttributedGraphML writes a graph in GraphML format,
+// with all edge attributes and the vertex labels
+func WriteAttributedGraphML(w io.Writer, A *Attributed) error {
+	return docFromAttributed(A).writeGraphML(w)
+}
+
+// ReadGraphML reads a graph in GraphML format. The weights are taken
+// from the edge attribute "weight" (1.0 if missing), the vertex labels
+// from the node attribute "label" or otherwise from the node IDs.
+func ReadGraphML(r io.Reader) (*Graph, error) {
+	d, err := readGraphML(r)
+	if err != nil {
+		return nil, err
+	}
+	G, err := d.graph()
+	if err != nil {
+		return nil, fmt.Errorf("graphml: %w", err)
+	}
+	return G, nil
+}
+
+// ReadAttributedGraphML reads a graph in GraphML format, with all
+// numerical edge attributes (in the order of their keys), which must
+// be given for every edge (or have a default value), and the vertex labels
+func ReadAttributedGraphML(r io.Reader) (*Attributed, error) {
+	d, err := readGraphML(r)
+	if err != nil {
+		return nil, err
+	}
+	A, err := d.attributed()
+	if err != nil {
+		return nil, fmt.Errorf("graphml: %w", err)
+	}
+	return A, nil
+}
+
+func (d *graphDoc) writeGraphML(w io.Writer) error {
+	doc := graphML{Xmlns: "http://graphml.graphdrawing.org/xmlns"}
+	doc.Keys = append(doc.Keys, graphMLKey{ID: "label", For: "node", Name: labelAttr, Type: "string"})
+	for i, name := range d.names {
+		doc.Keys = append(doc.Keys, graphMLKey{ID: "d" + strconv.Itoa(i), For: "edge", Name: name, Type: "double"})
+	}
+	g := graphMLGraph{ID: "G", EdgeDefault: "undirected"}
+	if d.directed {
+		g.EdgeDefault = "directed"
+	}
+	g.Nodes = make([]graphMLNode, len(d.labels))
+	for v, label := range d.labels {
+		g.Nodes[v].ID = "n" + strconv.Itoa(v)
+		if label != "" {
+			g.Nodes[v].Data = []graphMLData{{"label", label}}
+		}
+	}
+	g.Edges = make([]graphMLEdge, len(d.edges))
+	for i, e := range d.edges {
+		g.Edges[i] = graphMLEdge{Source: "n" + strconv.Itoa(e.from), Target: "n" + strconv.Itoa(e.to)}
+		for j, a := range e.attrs {
+			g.Edges[i].Data = append(g.Edges[i].Data, graphMLData{"d" + strconv.Itoa(j), formatFloat(a)})
+		}
+	}
+	doc.Graphs = []graphMLGraph{g}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// decode a GraphML file, only the first graph is read
+func readGraphML(r io.Reader) (*graphDoc, error) {
+	var doc graphML
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("graphml: %w", err)
+	}
+	if len(doc.Graphs) == 0 {
+		return nil, errors.New("graphml: no graph found")
+	}
+	g := doc.Graphs[0]
+	d := new(graphDoc)
+	switch g.EdgeDefault {
+	case "directed":
+		d.directed = true
+	case "undirected", "":
+	default:
+		return nil, fmt.Errorf("graphml: invalid edgedefault %q", g.EdgeDefault)
+	}
+
+	// the keys of the labels and numerical edge attributes
+	labelKey := ""
+	attr := make(map[string]int) // position of the edge attribute with a key
+	var defaults []float64
+	for _, k := range doc.Keys {
+		switch {
+		case (k.For == "node" || k.For == "all") && k.Name == labelAttr:
+			labelKey = k.ID
+		case (k.For == "edge" || k.For == "all") && isNumericType(k.Type):
+			if _, ok := attr[k.ID]; ok {
+				return nil, fmt.Errorf("graphml: duplicate key %q", k.ID)
+			}
+			def := math.NaN()
+			if k.Default != nil {
+				var err error
+				if def, err = strconv.ParseFloat(strings.TrimSpace(*k.Default), 64); err != nil {
+					return nil, fmt.Errorf("graphml: invalid default value of key %q", k.ID)
+				}
+			}
+			attr[k.ID] = len(d.names)
+			d.names = append(d.names, k.Name)
+			defaults = append(defaults, def)
+		}
+	}
+
+	index := make(map[string]int, len(g.Nodes)) // vertex of each node ID
+	d.labels = make([]string, len(g.Nodes))
+	for v, n := range g.Nodes {
+		if _, ok := index[n.ID]; ok {
+			return nil, fmt.Errorf("graphml: duplicate node %q", n.ID)
+		}
+		index[n.ID] = v
+		label := ""
+		for _, data := range n.Data {
+			if labelKey != "" && data.Key == labelKey {
+				label = data.Value
+			}
+		}
+		d.labels[v] = vertexLabel(label, n.ID, "n"+strconv.Itoa(v))
+	}
+
+	for _, edge := range g.Edges {
+		from, ok1 := index[edge.Source]
+		to, ok2 := index[edge.Target]
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("graphml: edge %q - %q: %w", edge.Source, edge.Target, ErrVertexRange)
+		}
+		e := docEdge{from, to, append([]float64(nil), defaults...)}
+		for _, data := range edge.Data {
+			i, ok := attr[data.Key]
+			if !ok {
+				continue
+			}
+			x, err := strconv.ParseFloat(strings.TrimSpace(data.Value), 64)
+			if err != nil {
+				return nil, fmt.Errorf("graphml: edge %q - %q: invalid value %q: %w", edge.Source, edge.Target, data.Value, ErrWeight)
+			}
+			e.attrs[i] = x
+		}
+		d.edges = append(d.edges, e)
+	}
+	return d, nil
+}
+
+func isNumericType(t string) bool {
+	return t == "double" || t == "float" || t == "int" || t == "long"
+}
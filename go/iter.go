@@ -0,0 +1,73 @@
+/* This file contains iterators over the edges of a graph,
+which can be used in for-range loops, e.g.
+
+	for e := range G.Edges() {
+		fmt.Println(e.From, e.To, e.Weight)
+	}
+*/
+
+package shortestpath
+
+import "iter"
+
+// Edges returns an iterator over all edges of the graph.
+// Each undirected edge is yielded once (with From <= To).
+func (G *Graph) Edges() iter.Seq[Edge] {
+	return func(yield func(Edge) bool) {
+		for u := 0; u < G.V; u++ {
+			for _, v := range G.adj[u] {
+				if !G.directed && v < u {
+					continue
+				}
+				if !yield(Edge{u, v, G.emat[u][v]}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Adjacent returns an iterator over the neighbours of vertex u
+// and the weights of the corresponding edges
+func (G *Graph) Adjacent(u int) iter.Seq2[int, float64] {
+	return func(yield func(int, float64) bool) {
+		for _, v := range G.adj[u] {
+			if !yield(v, G.emat[u][v]) {
+				return
+			}
+		}
+	}
+}
+
+// Edges returns an iterator over all edges of the graph.
+// Each undirected edge is yielded once (with From <= To).
+func (C *CSROf[W]) Edges() iter.Seq[EdgeOf[W]] {
+	return func(yield func(EdgeOf[W]) bool) {
+		for u := 0; u < C.V; u++ {
+			for k := C.Offsets[u]; k < C.Offsets[u+1]; k++ {
+				if !C.directed && C.Targets[k] < u {
+					continue
+				}
+				if !yield(EdgeOf[W]{u, C.Targets[k], C.Weights[k]}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Arcs returns an iterator over the edges leaving each vertex of any
+// graph G (source, target and weight). Undirected edges are yielded
+// in both directions, as seen by the shortest-path routines.
+func Arcs[W Number](G Weighted[W]) iter.Seq[EdgeOf[W]] {
+	return func(yield func(EdgeOf[W]) bool) {
+		stop := false
+		for u := 0; u < G.Order() && !stop; u++ {
+			G.VisitEdges(u, func(v int, w W) {
+				if !stop && !yield(EdgeOf[W]{u, v, w}) {
+					stop = true
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,177 @@
+/* This file contains a mapping between the vertex
+indices 0 to V-1 used by the algorithms and external
+identifiers of the vertices (e.g. station codes or OSM IDs). */
+
+package shortestpath
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errors returned for vertex labels
+var (
+	ErrUnknownLabel   = errors.New("unknown vertex label")
+	ErrDuplicateLabel = errors.New("duplicate vertex label")
+)
+
+// Labels maps vertex indices to unique string labels and back.
+// A nil *Labels is an empty mapping that can be read, but not set.
+type Labels struct {
+	names []string       // label of each vertex ("" if not set)
+	index map[string]int // vertex index of each label
+}
+
+// NewLabels returns an empty label mapping
+func NewLabels() *Labels {
+	L := new(Labels)
+	L.index = make(map[string]int)
+	return L
+}
+
+// Set sets the label of vertex v. An existing label of v is replaced,
+// but the label must not be used for another vertex already.
+func (L *Labels) Set(v int, name string) error {
+	if v < 0 {
+		return fmt.Errorf("label %q: %w", name, ErrVertexRange)
+	}
+	if u, ok := L.index[name]; ok && u != v {
+		return fmt.Errorf("label %q of vertex %d is used by vertex %d: %w", name, v, u, ErrDuplicateLabel)
+	}
+	for len(L.names) <= v {
+		L.names = append(L.names, "")
+	}
+	if old := L.names[v]; old != "" {
+		delete(L.index, old)
+	}
+	L.names[v] = name
+	if name != "" {
+		L.index[name] = v
+	}
+	return nil
+}
+
+// Name returns the label of vertex v ("" if it has no label)
+func (L *Labels) Name(v int) string {
+	if L == nil || v < 0 || v >= len(L.names) {
+		return ""
+	}
+	return L.names[v]
+}
+
+// Index returns the vertex with the given label
+func (L *Labels) Index(name string) (int, error) {
+	if L == nil {
+		return -1, fmt.Errorf("label %q: %w", name, ErrUnknownLabel)
+	}
+	v, ok := L.index[name]
+	if !ok {
+		return -1, fmt.Errorf("label %q: %w", name, ErrUnknownLabel)
+	}
+	return v, nil
+}
+
+// Len returns the number of labelled vertices
+func (L *Labels) Len() int {
+	if L == nil {
+		return 0
+	}
+	return len(L.index)
+}
+
+// Names translates a list of vertices (e.g. a path) into their labels
+func (L *Labels) Names(path []int) []string {
+	names := make([]string, len(path))
+	for i, v := range path {
+		names[i] = L.Name(v)
+	}
+	return names
+}
+
+// Indices translates a list of labels into the vertex indices
+func (L *Labels) Indices(names []string) ([]int, error) {
+	path := make([]int, len(names))
+	for i, name := range names {
+		v, err := L.Index(name)
+		if err != nil {
+			return nil, err
+		}
+		path[i] = v
+	}
+	return path, nil
+}
+
+// SetLabel sets the label of vertex v of the graph
+func (G *Graph) SetLabel(v int, name string) error {
+	if !G.hasVertex(v) {
+		return fmt.Errorf("label %q of vertex %d: %w", name, v, ErrVertexRange)
+	}
+	if G.labels == nil {
+		G.labels = NewLabels()
+	}
+	return G.labels.Set(v, name)
+}
+
+// Label returns the label of vertex v ("" if it has no label)
+func (G *Graph) Label(v int) string {
+	return G.labels.Name(v)
+}
+
+// Vertex returns the vertex with the given label,
+// e.g. to be used as start point for Dijkstra
+func (G *Graph) Vertex(name string) (int, error) {
+	return G.labels.Index(name)
+}
+
+// Labels returns the label mapping of the graph, which is nil
+// until the first label is set. It must only be changed with SetLabel.
+func (G *Graph) Labels() *Labels {
+	return G.labels
+}
+
+// AddEdgeLabel adds a new edge between the vertices with the labels
+// name1 and name2, otherwise it is the same as AddEdge
+func (G *Graph) AddEdgeLabel(name1, name2 string, l float64) error {
+	v1, err := G.Vertex(name1)
+	if err != nil {
+		return err
+	}
+	v2, err := G.Vertex(name2)
+	if err != nil {
+		return err
+	}
+	return G.AddEdge(v1, v2, l)
+}
+
+// LabelPath translates a path (e.g. from GetPathD or GetPathFW)
+// into the labels of its vertices
+func (G *Graph) LabelPath(path []int) []string {
+	return G.labels.Names(path)
+}
+
+// SetLabel sets the label of vertex v of the attributed graph
+func (A *Attributed) SetLabel(v int, name string) error {
+	if v < 0 || v >= A.Order() {
+		return fmt.Errorf("label %q of vertex %d: %w", name, v, ErrVertexRange)
+	}
+	if A.labels == nil {
+		A.labels = NewLabels()
+	}
+	return A.labels.Set(v, name)
+}
+
+// Label returns the label of vertex v ("" if it has no label)
+func (A *Attributed) Label(v int) string {
+	return A.labels.Name(v)
+}
+
+// Vertex returns the vertex with the given label
+func (A *Attributed) Vertex(name string) (int, error) {
+	return A.labels.Index(name)
+}
+
+// Labels returns the label mapping of the attributed graph, which is
+// nil until the first label is set. It must only be changed with SetLabel.
+func (A *Attributed) Labels() *Labels {
+	return A.labels
+}
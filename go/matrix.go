@@ -0,0 +1,325 @@
+/* This file contains routines to read and write graphs as matrices,
+i.e., in the same layout as the neighbour and edge matrices of Graph
+(and of the Fortran graph module), so that both implementations can
+be fed the identical input file:
+
+  - a plain dense weight matrix: comment lines starting with #,
+    a header line with the number of vertices (and optionally
+    "directed" or "undirected"), then one row of weights per vertex,
+    where 0 means that there is no edge
+  - the Matrix Market exchange format in coordinate (sparse)
+    or array (dense) form, see https://math.nist.gov/MatrixMarket
+
+Vertices are numbered from 1 in Matrix Market files. */
+
+package shortestpath
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// matrixScanner splits a text file into lines and fields,
+// skipping empty lines and comments
+type matrixScanner struct {
+	sc      *bufio.Scanner
+	comment string   // prefix of comment lines
+	line    int      // current line number
+	fields  []string // remaining fields of the current line
+}
+
+func newMatrixScanner(r io.Reader, comment string) *matrixScanner {
+	s := &matrixScanner{sc: bufio.NewScanner(r), comment: comment}
+	s.sc.Buffer(nil, 1<<26) // the rows of dense matrices can be long
+	return s
+}
+
+// nextLine returns the fields of the next line, or io.EOF
+func (s *matrixScanner) nextLine() ([]string, error) {
+	for s.sc.Scan() {
+		s.line++
+		text := strings.TrimSpace(s.sc.Text())
+		if text == "" || strings.HasPrefix(text, s.comment) {
+			continue
+		}
+		return strings.FieldsFunc(text, func(c rune) bool {
+			return c == ',' || c == ' ' || c == '\t'
+		}), nil
+	}
+	if err := s.sc.Err(); err != nil {
+		return nil, s.errorf("%w", err)
+	}
+	return nil, io.EOF
+}
+
+// nextFloat returns the next value, which may be on the next line
+func (s *matrixScanner) nextFloat() (float64, error) {
+	for len(s.fields) == 0 {
+		fields, err := s.nextLine()
+		if err == io.EOF {
+			return 0, &ParseError{s.line + 1, errors.New("unexpected end of file")}
+		} else if err != nil {
+			return 0, err
+		}
+		s.fields = fields
+	}
+	f := s.fields[0]
+	s.fields = s.fields[1:]
+	x, err := strconv.ParseFloat(f, 64)
+	if err != nil {
+		return 0, s.errorf("invalid value %q: %w", f, ErrWeight)
+	}
+	return x, nil
+}
+
+// the number of values left on the current line must be zero
+func (s *matrixScanner) endOfLine() error {
+	if len(s.fields) > 0 {
+		return s.errorf("too many values")
+	}
+	return nil
+}
+
+func (s *matrixScanner) errorf(format string, a ...any) error {
+	return &ParseError{s.line, fmt.Errorf(format, a...)}
+}
+
+// ReadMatrix reads a graph from a plain dense weight matrix
+func ReadMatrix(r io.Reader) (*Graph, error) {
+	s := newMatrixScanner(r, "#")
+	header, err := s.nextLine()
+	if err == io.EOF {
+		return nil, &ParseError{s.line + 1, errors.New("missing header")}
+	} else if err != nil {
+		return nil, err
+	}
+	if len(header) > 2 || (len(header) == 2 && !isDirectedness(header[1])) {
+		return nil, s.errorf("invalid header, want \"<vertices> [directed|undirected]\"")
+	}
+	V, err := strconv.Atoi(header[0])
+	if err != nil || V < 0 {
+		return nil, s.errorf("invalid number of vertices %q", header[0])
+	}
+	if err := checkOrder(V, MaxOrder); err != nil {
+		return nil, s.errorf("%w", err)
+	}
+	G := NewGraph()
+	G.directed = len(header) == 2 && header[1] == "directed"
+	G.SetOrder(V)
+
+	for i := 0; i < V; i++ {
+		for j := 0; j < V; j++ {
+			l, err := s.nextFloat()
+			if err != nil {
+				return nil, err
+			}
+			if l == 0 && (G.directed || j >= i) {
+				continue
+			}
+			if !G.directed && j < i {
+				// the lower triangle must be the same as the upper one
+				if l != G.emat[j][i] {
+					return nil, s.errorf("weight of edge (%d,%d) differs from (%d,%d) in an undirected graph", i, j, j, i)
+				}
+				continue
+			}
+			if err := G.AddEdge(i, j, l); err != nil {
+				return nil, s.errorf("%w", err)
+			}
+		}
+		if err := s.endOfLine(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := s.nextLine(); err != io.EOF {
+		return nil, s.errorf("too many rows")
+	}
+	return G, nil
+}
+
+// WriteMatrix writes a graph as a plain dense weight matrix.
+// As 0 means that there is no edge, weights of 0 can't be written.
+func WriteMatrix(w io.Writer, G *Graph) error {
+	bw := bufio.NewWriter(w)
+	kind := "undirected"
+	if G.directed {
+		kind = "directed"
+	}
+	fmt.Fprintln(bw, "# weight matrix, 0 = no edge")
+	fmt.Fprintf(bw, "%d %s\n", G.V, kind)
+	for i := 0; i < G.V; i++ {
+		for j := 0; j < G.V; j++ {
+			if G.nmat[i][j] == 1 && G.emat[i][j] == 0 {
+				return &EdgeError{"WriteMatrix", i, j, ErrWeight}
+			}
+			if j > 0 {
+				bw.WriteByte(' ')
+			}
+			bw.WriteString(formatFloat(G.emat[i][j]))
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// ReadMatrixMarket reads a graph from a Matrix Market file with real,
+// integer or pattern (all weights 1.0) entries. General matrices give
+// a directed graph, symmetric ones an undirected graph. In array format
+// entries of 0 mean that there is no edge.
+func ReadMatrixMarket(r io.Reader) (*Graph, error) {
+	s := newMatrixScanner(r, "%")
+	if !s.sc.Scan() {
+		return nil, &ParseError{1, errors.New("missing %%MatrixMarket header")}
+	}
+	s.line++
+	banner := strings.Fields(strings.ToLower(s.sc.Text()))
+	if len(banner) != 5 || banner[0] != "%%matrixmarket" || banner[1] != "matrix" {
+		return nil, s.errorf("invalid header, want \"%%%%MatrixMarket matrix <format> <field> <symmetry>\"")
+	}
+	format, field, symmetry := banner[2], banner[3], banner[4]
+	if format != "coordinate" && format != "array" {
+		return nil, s.errorf("unknown format %q", format)
+	}
+	if field != "real" && field != "integer" && (field != "pattern" || format != "coordinate") {
+		return nil, s.errorf("unsupported field %q", field)
+	}
+	if symmetry != "general" && symmetry != "symmetric" {
+		return nil, s.errorf("unsupported symmetry %q", symmetry)
+	}
+
+	size, err := s.nextLine()
+	if err == io.EOF {
+		return nil, &ParseError{s.line + 1, errors.New("missing size line")}
+	} else if err != nil {
+		return nil, err
+	}
+	want := 2
+	if format == "coordinate" {
+		want = 3
+	}
+	if len(size) != want {
+		return nil, s.errorf("expected %d numbers in the size line, got %d", want, len(size))
+	}
+	dims := make([]int, want)
+	for i, f := range size {
+		if dims[i], err = strconv.Atoi(f); err != nil || dims[i] < 0 {
+			return nil, s.errorf("invalid size %q", f)
+		}
+	}
+	if dims[0] != dims[1] {
+		return nil, s.errorf("the matrix of a graph must be square, got %dx%d", dims[0], dims[1])
+	}
+	V := dims[0]
+	if err := checkOrder(V, MaxOrder); err != nil {
+		return nil, s.errorf("%w", err)
+	}
+	G := NewGraph()
+	G.directed = symmetry == "general"
+	G.SetOrder(V)
+
+	if format == "array" {
+		// the values are stored column by column,
+		// only the lower triangle for symmetric matrices
+		for j := 0; j < V; j++ {
+			i0 := 0
+			if !G.directed {
+				i0 = j
+			}
+			for i := i0; i < V; i++ {
+				l, err := s.nextFloat()
+				if err != nil {
+					return nil, err
+				}
+				if l == 0 {
+					continue
+				}
+				if err := G.AddEdge(i, j, l); err != nil {
+					return nil, s.errorf("%w", err)
+				}
+			}
+		}
+	} else {
+		for k := 0; k < dims[2]; k++ {
+			entry, err := s.nextLine()
+			if err == io.EOF {
+				return nil, &ParseError{s.line + 1, fmt.Errorf("expected %d entries, got %d", dims[2], k)}
+			} else if err != nil {
+				return nil, err
+			}
+			want := 3
+			if field == "pattern" {
+				want = 2
+			}
+			if len(entry) != want {
+				return nil, s.errorf("expected %d fields, got %d", want, len(entry))
+			}
+			i, err1 := strconv.Atoi(entry[0])
+			j, err2 := strconv.Atoi(entry[1])
+			if err1 != nil || err2 != nil {
+				return nil, s.errorf("invalid entry %q", strings.Join(entry, " "))
+			}
+			l := 1.0
+			if field != "pattern" {
+				if l, err = strconv.ParseFloat(entry[2], 64); err != nil {
+					return nil, s.errorf("invalid value %q: %w", entry[2], ErrWeight)
+				}
+			}
+			if err := G.AddEdge(i-1, j-1, l); err != nil {
+				return nil, s.errorf("%w", err)
+			}
+		}
+	}
+	if _, err := s.nextLine(); err != io.EOF {
+		return nil, s.errorf("too many entries")
+	}
+	return G, nil
+}
+
+// WriteMatrixMarket writes a graph as a sparse Matrix Market file
+// (coordinate format), symmetric for undirected graphs
+func WriteMatrixMarket(w io.Writer, G *Graph) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%%%%MatrixMarket matrix coordinate real %s\n", mmSymmetry(G))
+	fmt.Fprintf(bw, "%d %d %d\n", G.V, G.V, G.E)
+	for e := range G.Edges() {
+		i, j := e.From, e.To
+		if !G.directed { // the lower triangle is stored
+			i, j = j, i
+		}
+		fmt.Fprintf(bw, "%d %d %s\n", i+1, j+1, formatFloat(e.Weight))
+	}
+	return bw.Flush()
+}
+
+// WriteMatrixMarketArray writes a graph as a dense Matrix Market file
+// (array format), symmetric for undirected graphs.
+// As 0 means that there is no edge, weights of 0 can't be written.
+func WriteMatrixMarketArray(w io.Writer, G *Graph) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%%%%MatrixMarket matrix array real %s\n", mmSymmetry(G))
+	fmt.Fprintf(bw, "%d %d\n", G.V, G.V)
+	for j := 0; j < G.V; j++ {
+		i0 := 0
+		if !G.directed {
+			i0 = j
+		}
+		for i := i0; i < G.V; i++ {
+			if G.nmat[i][j] == 1 && G.emat[i][j] == 0 {
+				return &EdgeError{"WriteMatrixMarketArray", i, j, ErrWeight}
+			}
+			fmt.Fprintln(bw, formatFloat(G.emat[i][j]))
+		}
+	}
+	return bw.Flush()
+}
+
+func mmSymmetry(G *Graph) string {
+	if G.directed {
+		return "general"
+	}
+	return "symmetric"
+}
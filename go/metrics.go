@@ -0,0 +1,93 @@
+/* This file contains routines to characterize a graph by its
+shortest-path distances: eccentricity, diameter, radius, center,
+periphery and the average shortest path length. They can be
+computed from the all-pairs result of FloydWarshall or, for large
+sparse graphs, from repeated single-source searches. */
+
+package shortestpath
+
+import "math"
+
+// Metrics are the distance-based properties of a graph.
+// If not every vertex can be reached from every other vertex,
+// the eccentricities (and the diameter) are +Inf.
+type Metrics struct {
+	Eccentricity  []float64 // the largest distance from each vertex to any other vertex
+	Diameter      float64   // the largest eccentricity
+	Radius        float64   // the smallest eccentricity
+	Center        []int     // the vertices with the smallest eccentricity
+	Periphery     []int     // the vertices with the largest eccentricity
+	AverageLength float64   // the average distance between all connected pairs of vertices
+	Pairs         int       // number of (ordered) pairs of connected vertices
+}
+
+// MetricsFromDistances calculates the metrics of a graph
+// from its distance matrix, e.g. the one returned by FloydWarshall
+func MetricsFromDistances(dist [][]float64) *Metrics {
+	M := newMetrics(len(dist))
+	for u, row := range dist {
+		M.addRow(u, row)
+	}
+	M.finish()
+	return M
+}
+
+// GraphMetrics calculates the metrics of a graph by running
+// DijkstraFibonacci from every vertex. Opposed to FloydWarshall
+// only O(V) memory is needed, but the edge weights must not be negative.
+func GraphMetrics(G Interface) *Metrics {
+	M := newMetrics(G.Order())
+	for u := 0; u < G.Order(); u++ {
+		dist, _ := DijkstraFibonacci(G, u)
+		M.addRow(u, dist)
+	}
+	M.finish()
+	return M
+}
+
+func newMetrics(V int) *Metrics {
+	M := new(Metrics)
+	M.Eccentricity = make([]float64, V)
+	return M
+}
+
+// add the distances from vertex u to all other vertices
+// (the sum of the distances is kept in AverageLength until finish)
+func (M *Metrics) addRow(u int, dist []float64) {
+	for v, d := range dist {
+		if v == u {
+			continue
+		}
+		if d > M.Eccentricity[u] {
+			M.Eccentricity[u] = d
+		}
+		if !math.IsInf(d, 0) {
+			M.AverageLength += d
+			M.Pairs++
+		}
+	}
+}
+
+// find the diameter, radius, center and periphery
+func (M *Metrics) finish() {
+	if M.Pairs > 0 {
+		M.AverageLength /= float64(M.Pairs)
+	}
+	if len(M.Eccentricity) == 0 {
+		return
+	}
+	M.Diameter = M.Eccentricity[0]
+	M.Radius = M.Eccentricity[0]
+	for _, e := range M.Eccentricity {
+		M.Diameter = math.Max(M.Diameter, e)
+		M.Radius = math.Min(M.Radius, e)
+	}
+	for v, e := range M.Eccentricity {
+		if e == M.Radius {
+			M.Center = append(M.Center, v)
+		}
+		if e == M.Diameter {
+			M.Periphery = append(M.Periphery, v)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !unix
+
+package shortestpath
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads a file into memory, as memory mapping is not supported
+func mapFile(f *os.File, size int) ([]byte, bool, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, false, err
+	}
+	return data, false, nil
+}
+
+func unmapFile(data []byte) error {
+	return nil
+}
@@ -0,0 +1,169 @@
+/* This file contains a multigraph, i.e., a graph in which
+more than one edge can exist between two vertices
+(e.g., a toll road and a free road between two cities).
+Each edge gets a unique ID, which can be used to find out
+which of the parallel edges a shortest path runs over. */
+
+package shortestpath
+
+import "math"
+
+// Multigraph is a graph that allows parallel edges.
+// The edge with ID i is stored in Edges[i].
+type Multigraph struct {
+	V        int     // number of vertices (order of the graph)
+	E        int     // number of edges (size of the graph)
+	Edges    []Edge  // all edges that were ever added, indexed by their ID
+	adj      [][]int // IDs of the edges leaving each vertex
+	deleted  []bool  // has the edge with a given ID been deleted?
+	directed bool    // is the graph a directed graph?
+}
+
+// NewMultigraph returns a multigraph with V vertices and no edges
+func NewMultigraph(V int, directed bool) *Multigraph {
+	M := new(Multigraph)
+	M.V = V
+	M.adj = make([][]int, V)
+	M.directed = directed
+	return M
+}
+
+// Directed reports whether M is a directed graph
+func (M *Multigraph) Directed() bool {
+	return M.directed
+}
+
+// AddEdge adds a new edge between two vertices and returns its ID.
+// Parallel edges are allowed, self-loops and non-finite weights are not.
+// If the edge cannot be added an *EdgeError is returned.
+func (M *Multigraph) AddEdge(v1, v2 int, l float64) (int, error) {
+	if v1 < 0 || v1 >= M.V || v2 < 0 || v2 >= M.V {
+		return -1, &EdgeError{"AddEdge", v1, v2, ErrVertexRange}
+	}
+	if v1 == v2 {
+		return -1, &EdgeError{"AddEdge", v1, v2, ErrSelfLoop}
+	}
+	if math.IsNaN(l) || math.IsInf(l, 0) {
+		return -1, &EdgeError{"AddEdge", v1, v2, ErrWeight}
+	}
+	id := len(M.Edges)
+	M.Edges = append(M.Edges, Edge{v1, v2, l})
+	M.deleted = append(M.deleted, false)
+	M.adj[v1] = append(M.adj[v1], id)
+	if !M.directed {
+		M.adj[v2] = append(M.adj[v2], id)
+	}
+	M.E++ //update size of M
+	return id, nil
+}
+
+// DelEdge deletes the edge with the given ID. The IDs of
+// all other edges stay the same and the ID is not reused.
+func (M *Multigraph) DelEdge(id int) error {
+	if !M.HasEdge(id) {
+		if id >= 0 && id < len(M.Edges) {
+			return &EdgeError{"DelEdge", M.Edges[id].From, M.Edges[id].To, ErrMissingEdge}
+		}
+		return &EdgeError{"DelEdge", -1, -1, ErrMissingEdge}
+	}
+	e := M.Edges[id]
+	M.adj[e.From] = removeID(M.adj[e.From], id)
+	if !M.directed {
+		M.adj[e.To] = removeID(M.adj[e.To], id)
+	}
+	M.deleted[id] = true
+	M.E-- //update size of M
+	return nil
+}
+
+// remove an edge ID from a list of IDs
+func removeID(ids []int, id int) []int {
+	for i, k := range ids {
+		if k == id {
+			return append(ids[:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
+
+// HasEdge runs a check on a graph: is the edge with the given ID part of the graph?
+func (M *Multigraph) HasEdge(id int) bool {
+	return id >= 0 && id < len(M.Edges) && !M.deleted[id]
+}
+
+// EdgesBetween returns the IDs of all (parallel) edges from v1 to v2
+func (M *Multigraph) EdgesBetween(v1, v2 int) []int {
+	var ids []int
+	M.VisitEdgeIDs(v1, func(v int, w float64, id int) {
+		if v == v2 {
+			ids = append(ids, id)
+		}
+	})
+	return ids
+}
+
+// Order returns the number of vertices (order of the graph)
+func (M *Multigraph) Order() int { return M.V }
+
+// VisitEdges calls fn for every edge leaving vertex u with the
+// neighbour v and the weight of the edge. For parallel edges
+// fn is called several times with the same neighbour.
+func (M *Multigraph) VisitEdges(u int, fn func(v int, w float64)) {
+	M.VisitEdgeIDs(u, func(v int, w float64, id int) {
+		fn(v, w)
+	})
+}
+
+// VisitEdgeIDs is like VisitEdges, but also passes the ID of each edge to fn
+func (M *Multigraph) VisitEdgeIDs(u int, fn func(v int, w float64, id int)) {
+	for _, id := range M.adj[u] {
+		e := M.Edges[id]
+		v := e.To
+		if v == u {
+			v = e.From
+		}
+		fn(v, e.Weight, id)
+	}
+}
+
+// lightest returns the ID of the edge with the smallest weight from v1 to v2
+// (the one a shortest path runs over) or -1 if there is no such edge
+func (M *Multigraph) lightest(v1, v2 int) int {
+	best := -1
+	M.VisitEdgeIDs(v1, func(v int, w float64, id int) {
+		if v == v2 && (best == -1 || w < M.Edges[best].Weight) {
+			best = id
+		}
+	})
+	return best
+}
+
+// PathEdges returns the IDs of the edges a path runs over, e.g. a path
+// obtained from GetPathD or GetPathFW. Between two consecutive vertices of
+// the path the edge with the smallest weight is chosen.
+func (M *Multigraph) PathEdges(path []int) ([]int, error) {
+	var ids []int
+	for i := 1; i < len(path); i++ {
+		id := M.lightest(path[i-1], path[i])
+		if id == -1 {
+			return nil, &EdgeError{"PathEdges", path[i-1], path[i], ErrMissingEdge}
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// TreeEdges returns for every vertex v the ID of the edge from prev[v] to v,
+// where prev are the predecessors returned by Dijkstra, DijkstraFibonacci
+// or BellmanFord. For the start point and unreachable vertices it is -1.
+func (M *Multigraph) TreeEdges(prev []int) []int {
+	ids := make([]int, len(prev))
+	for v, u := range prev {
+		if u == -1 || u == v {
+			ids[v] = -1
+		} else {
+			ids[v] = M.lightest(u, v)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,187 @@
+/* This file contains routines to read and write graphs in the
+node-link JSON format of NetworkX (node_link_data/node_link_graph),
+which is also understood by d3.js. A small example:
+
+	{"directed": false, "multigraph": false, "graph": {},
+	 "nodes": [{"id": 0, "label": "A"}, {"id": 1}],
+	 "links": [{"source": 0, "target": 1, "weight": 1.85}]}
+
+The edge list may also be called "edges" (as in newer NetworkX versions). */
+
+package shortestpath
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+)
+
+type nodeLink struct {
+	Directed   bool             `json:"directed"`
+	Multigraph bool             `json:"multigraph"`
+	Graph      map[string]any   `json:"graph"`
+	Nodes      []map[string]any `json:"nodes"`
+	Links      []map[string]any `json:"links"`
+	Edges      []map[string]any `json:"edges,omitempty"`
+}
+
+// the fields of a link that are not attributes
+var linkFields = []string{"source", "target", "key"}
+
+// WriteJSON writes a graph in node-link JSON format,
+// with the weights and vertex labels
+func WriteJSON(w io.Writer, G *Graph) error {
+	return docFromGraph(G).writeJSON(w, false)
+}
+
+// WriteAttributedJSON writes a graph in node-link JSON format,
+// with all edge attributes and the vertex labels. The order of
+// the attributes is kept in the graph attribute "attributes".
+func WriteAttributedJSON(w io.Writer, A *Attributed) error {
+	return docFromAttributed(A).writeJSON(w, true)
+}
+
+// ReadJSON reads a graph in node-link JSON format. The weights are taken
+// from the link attribute "weight" (1.0 if missing), the vertex labels
+// from the node attribute "label" or otherwise from the node IDs.
+func ReadJSON(r io.Reader) (*Graph, error) {
+	d, err := readJSON(r)
+	if err != nil {
+		return nil, err
+	}
+	G, err := d.graph()
+	if err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+	return G, nil
+}
+
+// ReadAttributedJSON reads a graph in node-link JSON format, with all
+// numerical link attributes and the weight (in the order given by the graph attribute
+// "attributes", otherwise sorted by name), which must be given for every edge,
+// and the vertex labels
+func ReadAttributedJSON(r io.Reader) (*Attributed, error) {
+	d, err := readJSON(r)
+	if err != nil {
+		return nil, err
+	}
+	A, err := d.attributed()
+	if err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+	return A, nil
+}
+
+func (d *graphDoc) writeJSON(w io.Writer, multigraph bool) error {
+	doc := nodeLink{Directed: d.directed, Multigraph: multigraph, Graph: map[string]any{}}
+	if multigraph {
+		doc.Graph["attributes"] = d.names
+	}
+	for _, name := range d.names {
+		if slices.Contains(linkFields, name) {
+			return fmt.Errorf("json: attribute name %q is reserved: %w", name, ErrAttributes)
+		}
+	}
+	doc.Nodes = make([]map[string]any, len(d.labels))
+	for v, label := range d.labels {
+		doc.Nodes[v] = map[string]any{"id": v}
+		if label != "" {
+			doc.Nodes[v][labelAttr] = label
+		}
+	}
+	doc.Links = make([]map[string]any, len(d.edges))
+	for i, e := range d.edges {
+		doc.Links[i] = map[string]any{"source": e.from, "target": e.to}
+		for j, a := range e.attrs {
+			doc.Links[i][d.names[j]] = a
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func readJSON(r io.Reader) (*graphDoc, error) {
+	var doc nodeLink
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+	links := doc.Links
+	if links == nil {
+		links = doc.Edges
+	}
+	d := &graphDoc{directed: doc.Directed}
+
+	// the vertex of each node ID, the IDs can be numbers or strings
+	index := make(map[string]int, len(doc.Nodes))
+	key := func(id any) string { return fmt.Sprintf("%T %v", id, id) }
+	d.labels = make([]string, len(doc.Nodes))
+	for v, n := range doc.Nodes {
+		id, ok := n["id"]
+		if !ok || id == nil {
+			return nil, fmt.Errorf("json: node %d has no id", v)
+		}
+		if _, ok := index[key(id)]; ok {
+			return nil, fmt.Errorf("json: duplicate node %v", id)
+		}
+		index[key(id)] = v
+		label := ""
+		if l, ok := n[labelAttr]; ok && l != nil {
+			label = fmt.Sprint(l)
+		}
+		d.labels[v] = vertexLabel(label, fmt.Sprint(id), strconv.Itoa(v))
+	}
+
+	// the names of the attributes
+	if names, ok := doc.Graph["attributes"].([]any); ok {
+		for _, name := range names {
+			s, ok := name.(string)
+			if !ok || slices.Contains(d.names, s) {
+				return nil, errors.New("json: invalid list of attributes")
+			}
+			d.names = append(d.names, s)
+		}
+	} else {
+		for _, l := range links {
+			for name, x := range l {
+				if _, ok := x.(json.Number); (ok || name == weightAttr) && !slices.Contains(linkFields, name) && !slices.Contains(d.names, name) {
+					d.names = append(d.names, name)
+				}
+			}
+		}
+		slices.Sort(d.names)
+	}
+
+	for i, l := range links {
+		from, ok1 := index[key(l["source"])]
+		to, ok2 := index[key(l["target"])]
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("json: link %d (%v - %v): %w", i, l["source"], l["target"], ErrVertexRange)
+		}
+		e := docEdge{from, to, make([]float64, len(d.names))}
+		for j, name := range d.names {
+			e.attrs[j] = math.NaN() // missing
+			x, ok := l[name]
+			if !ok {
+				continue
+			}
+			n, ok := x.(json.Number)
+			if !ok {
+				return nil, fmt.Errorf("json: link %d: attribute %q is not a number: %w", i, name, ErrWeight)
+			}
+			f, err := n.Float64()
+			if err != nil {
+				return nil, fmt.Errorf("json: link %d: attribute %q: %w", i, name, ErrWeight)
+			}
+			e.attrs[j] = f
+		}
+		d.edges = append(d.edges, e)
+	}
+	return d, nil
+}
@@ -0,0 +1,277 @@
+/* This file contains an importer for OpenStreetMap XML files (.osm),
+which turns the roads of an extract into a routable graph: the ways
+are filtered by their highway tag and split at intersections, so that
+the vertices are the intersections and dead ends, and the edges are
+the road segments between them with their length in meters as weight.
+See https://wiki.openstreetmap.org/wiki/OSM_XML */
+
+package shortestpath
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// DefaultHighways are the values of the highway tag of roads for cars
+var DefaultHighways = []string{
+	"motorway", "motorway_link", "trunk", "trunk_link",
+	"primary", "primary_link", "secondary", "secondary_link",
+	"tertiary", "tertiary_link", "unclassified", "residential",
+	"living_street", "service", "road",
+}
+
+// OSMOptions control which roads are imported by ReadOSM
+type OSMOptions struct {
+	Highways     []string // values of the highway tag to import (DefaultHighways if nil)
+	IgnoreOneway bool     // import all roads in both directions as an undirected graph
+}
+
+// RoadNetwork is a road network imported from OpenStreetMap
+type RoadNetwork struct {
+	Edges    []Edge  // road segments between the vertices, the weights are lengths in meters
+	Coords   []Point // coordinates of the vertices, X is the longitude and Y the latitude
+	IDs      []int64 // OSM node IDs of the vertices
+	Directed bool    // are the edges directed (i.e., oneway roads were kept)?
+}
+
+// the parts of the OSM elements that are needed
+type osmWay struct {
+	refs []int64
+	tags map[string]string
+}
+
+// EarthRadius is the mean radius of the earth in meters
+const EarthRadius = 6371008.8
+
+// Haversine returns the great-circle distance in meters between
+// two points given by their longitude (X) and latitude (Y) in degrees
+func Haversine(a, b Point) float64 {
+	rad := math.Pi / 180
+	dlat := (b.Y - a.Y) * rad
+	dlon := (b.X - a.X) * rad
+	h := math.Sin(dlat/2)*math.Sin(dlat/2) +
+		math.Cos(a.Y*rad)*math.Cos(b.Y*rad)*math.Sin(dlon/2)*math.Sin(dlon/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// ReadOSM imports the roads of an OpenStreetMap XML file.
+// Use nil options for the defaults.
+func ReadOSM(r io.Reader, opt *OSMOptions) (*RoadNetwork, error) {
+	if opt == nil {
+		opt = new(OSMOptions)
+	}
+	highways := opt.Highways
+	if highways == nil {
+		highways = DefaultHighways
+	}
+
+	nodes := make(map[int64]Point)
+	var ways []osmWay
+	dec := xml.NewDecoder(r)
+	var way *osmWay // the way that is currently read
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, osmError(dec, err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "node":
+				var id int64
+				var p Point
+				if err := osmAttrs(t, map[string]any{"id": &id, "lon": &p.X, "lat": &p.Y}); err != nil {
+					return nil, osmError(dec, err)
+				}
+				if p.X < -180 || p.X > 180 || p.Y < -90 || p.Y > 90 {
+					return nil, osmError(dec, fmt.Errorf("coordinates (%g, %g) of node %d out of range", p.X, p.Y, id))
+				}
+				nodes[id] = p
+			case "way":
+				way = &osmWay{tags: make(map[string]string)}
+			case "nd":
+				if way != nil {
+					var ref int64
+					if err := osmAttrs(t, map[string]any{"ref": &ref}); err != nil {
+						return nil, osmError(dec, err)
+					}
+					way.refs = append(way.refs, ref)
+				}
+			case "tag":
+				if way != nil {
+					var k, v string
+					for _, a := range t.Attr {
+						switch a.Name.Local {
+						case "k":
+							k = a.Value
+						case "v":
+							v = a.Value
+						}
+					}
+					way.tags[k] = v
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "way" && way != nil {
+				if slices.Contains(highways, way.tags["highway"]) {
+					ways = append(ways, *way)
+				}
+				way = nil
+			}
+		}
+	}
+	return buildRoadNetwork(nodes, ways, !opt.IgnoreOneway), nil
+}
+
+// split the ways at the intersections, i.e., the nodes that are used
+// more than once, and at the first and last node of each way
+func buildRoadNetwork(nodes map[int64]Point, ways []osmWay, oneway bool) *RoadNetwork {
+	N := &RoadNetwork{Directed: oneway}
+	uses := make(map[int64]int)
+	for _, w := range ways {
+		for i, ref := range w.refs {
+			uses[ref]++
+			if i == 0 || i == len(w.refs)-1 {
+				uses[ref]++ // the ends of a way are always vertices
+			}
+		}
+	}
+	index := make(map[int64]int) // vertex of each OSM node
+	vertex := func(ref int64) int {
+		v, ok := index[ref]
+		if !ok {
+			v = len(N.IDs)
+			index[ref] = v
+			N.IDs = append(N.IDs, ref)
+			N.Coords = append(N.Coords, nodes[ref])
+		}
+		return v
+	}
+
+	for _, w := range ways {
+		forward, backward := true, true
+		if oneway {
+			forward, backward = onewayDirections(w.tags)
+		}
+		start := -1 // OSM node where the current segment started
+		length := 0.0
+		for i, ref := range w.refs {
+			p, ok := nodes[ref]
+			if !ok { // the node is not part of the extract
+				start = -1
+				continue
+			}
+			if start == -1 {
+				start, length = i, 0
+				continue
+			}
+			length += Haversine(nodes[w.refs[i-1]], p)
+			if uses[ref] < 2 && i < len(w.refs)-1 {
+				if _, ok := nodes[w.refs[i+1]]; ok {
+					continue // not an intersection
+				}
+			}
+			u, v := vertex(w.refs[start]), vertex(ref)
+			if u != v {
+				if forward {
+					N.Edges = append(N.Edges, Edge{u, v, length})
+				}
+				if backward && oneway {
+					N.Edges = append(N.Edges, Edge{v, u, length})
+				}
+			}
+			start, length = i, 0
+		}
+	}
+	return N
+}
+
+// onewayDirections returns in which directions a way can be used
+func onewayDirections(tags map[string]string) (forward, backward bool) {
+	switch tags["oneway"] {
+	case "yes", "true", "1":
+		return true, false
+	case "-1", "reverse":
+		return false, true
+	case "no", "false", "0":
+		return true, true
+	}
+	// oneway is implied for motorways and roundabouts
+	if tags["highway"] == "motorway" || tags["junction"] == "roundabout" {
+		return true, false
+	}
+	return true, true
+}
+
+// Graph returns the road network as a Graph, the OSM node IDs are used
+// as vertex labels. Of parallel road segments only the shortest is kept.
+// Networks with more than MaxOrder vertices are rejected with ErrOrder,
+// use CSR for them.
+func (N *RoadNetwork) Graph() (*Graph, error) {
+	if err := checkOrder(len(N.IDs), MaxOrder); err != nil {
+		return nil, err
+	}
+	G := NewGraph()
+	G.directed = N.Directed
+	G.SetParallel(KeepMin)
+	G.SetOrder(len(N.IDs))
+	for v, id := range N.IDs {
+		if err := G.SetLabel(v, strconv.FormatInt(id, 10)); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range N.Edges {
+		if err := G.AddEdge(e.From, e.To, e.Weight); err != nil {
+			return nil, err
+		}
+	}
+	return G, nil
+}
+
+// CSR returns the road network as a CSR graph, which is more suitable
+// for large networks. Of parallel road segments only the shortest is kept.
+func (N *RoadNetwork) CSR() (*CSR, error) {
+	if err := checkOrder(len(N.IDs), MaxCSROrder); err != nil {
+		return nil, err
+	}
+	return NewCSRParallel(len(N.IDs), N.Directed, N.Edges, KeepMin)
+}
+
+// read the attributes of an element into integers or floats
+func osmAttrs(t xml.StartElement, attrs map[string]any) error {
+	found := 0
+	for _, a := range t.Attr {
+		var err error
+		switch x := attrs[a.Name.Local].(type) {
+		case *int64:
+			*x, err = strconv.ParseInt(a.Value, 10, 64)
+		case *float64:
+			*x, err = strconv.ParseFloat(a.Value, 64)
+			if err == nil && (math.IsNaN(*x) || math.IsInf(*x, 0)) {
+				err = strconv.ErrSyntax
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s %q of <%s>", a.Name.Local, a.Value, t.Name.Local)
+		}
+		found++
+	}
+	if found != len(attrs) {
+		return fmt.Errorf("missing attribute of <%s>", t.Name.Local)
+	}
+	return nil
+}
+
+// osmError adds the line number to an error
+func osmError(dec *xml.Decoder, err error) error {
+	line, _ := dec.InputPos()
+	return &ParseError{line, err}
+}
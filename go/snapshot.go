@@ -0,0 +1,315 @@
+/* This file contains a compact binary format for CSR graphs.
+A snapshot can be memory-mapped and queried right away, as the
+arrays of the graph are used directly from the mapped file
+(on little-endian 64-bit machines, otherwise they are copied).
+All numbers are stored in little-endian byte order:
+
+	header (64 bytes):
+	  0  magic "SPCSRBIN"
+	  8  version (uint32), flags (uint32): 1 = directed, 2 = coordinates
+	  16 V, E, number of entries n of Targets (uint64 each)
+	  40 CRC-32C of the data, CRC-32C of the header bytes 0-43 (uint32 each)
+	  48 reserved (zero)
+	data:
+	  Offsets (V+1 int64), Targets (n int64), Weights (n float64),
+	  coordinates (V times x, y float64) if flag 2 is set */
+
+package shortestpath
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"unsafe"
+)
+
+const (
+	snapshotMagic   = "SPCSRBIN"
+	snapshotVersion = 1
+	snapshotHeader  = 64
+
+	snapshotDirected    = 1 << 0
+	snapshotCoordinates = 1 << 1
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// Snapshot is a CSR graph loaded from a binary snapshot,
+// optionally with the coordinates of its vertices.
+// If the snapshot is memory-mapped, the slices of the graph and
+// the coordinates are read-only and can't be used after Close.
+type Snapshot struct {
+	*CSR
+	Coords   []Point // coordinates of the vertices (nil if not stored)
+	data     []byte  // content of the file
+	mapped   bool    // is data memory-mapped?
+	checksum uint32  // CRC-32C of the data stored in the header
+}
+
+// WriteSnapshot writes a CSR graph (and optionally the coordinates
+// of its vertices, nil otherwise) as a binary snapshot
+func WriteSnapshot(w io.Writer, C *CSR, coords []Point) error {
+	if coords != nil && len(coords) != C.V {
+		return fmt.Errorf("%d coordinates for %d vertices: %w", len(coords), C.V, ErrSnapshot)
+	}
+	crc := crc32.New(castagnoli)
+	if err := writeSnapshotData(crc, C, coords); err != nil {
+		return err
+	}
+
+	header := make([]byte, snapshotHeader)
+	copy(header, snapshotMagic)
+	flags := uint32(0)
+	if C.directed {
+		flags |= snapshotDirected
+	}
+	if coords != nil {
+		flags |= snapshotCoordinates
+	}
+	binary.LittleEndian.PutUint32(header[8:], snapshotVersion)
+	binary.LittleEndian.PutUint32(header[12:], flags)
+	binary.LittleEndian.PutUint64(header[16:], uint64(C.V))
+	binary.LittleEndian.PutUint64(header[24:], uint64(C.E))
+	binary.LittleEndian.PutUint64(header[32:], uint64(len(C.Targets)))
+	binary.LittleEndian.PutUint32(header[40:], crc.Sum32())
+	binary.LittleEndian.PutUint32(header[44:], crc32.Checksum(header[:44], castagnoli))
+
+	bw := bufio.NewWriter(w)
+	bw.Write(header)
+	if err := writeSnapshotData(bw, C, coords); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// write the arrays of a snapshot
+func writeSnapshotData(w io.Writer, C *CSR, coords []Point) error {
+	buf := make([]byte, 0, 1<<16)
+	flush := func() error {
+		_, err := w.Write(buf)
+		buf = buf[:0]
+		return err
+	}
+	put := func(x uint64) error {
+		buf = binary.LittleEndian.AppendUint64(buf, x)
+		if len(buf) == cap(buf) {
+			return flush()
+		}
+		return nil
+	}
+	for _, o := range C.Offsets {
+		if err := put(uint64(o)); err != nil {
+			return err
+		}
+	}
+	for _, t := range C.Targets {
+		if err := put(uint64(t)); err != nil {
+			return err
+		}
+	}
+	for _, l := range C.Weights {
+		if err := put(math.Float64bits(l)); err != nil {
+			return err
+		}
+	}
+	for _, p := range coords {
+		if err := put(math.Float64bits(p.X)); err != nil {
+			return err
+		}
+		if err := put(math.Float64bits(p.Y)); err != nil {
+			return err
+		}
+	}
+	return flush()
+}
+
+// OpenSnapshot memory-maps a binary snapshot. Only the header is
+// checked, call Verify to check the checksum and structure of the data.
+// The snapshot must be closed when it is not needed anymore.
+func OpenSnapshot(name string) (*Snapshot, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < snapshotHeader || info.Size() > math.MaxInt {
+		return nil, fmt.Errorf("%s: file size %d: %w", name, info.Size(), ErrSnapshot)
+	}
+	data, mapped, err := mapFile(f, int(info.Size()))
+	if err != nil {
+		return nil, err
+	}
+	S, err := loadSnapshot(data)
+	if err != nil {
+		if mapped {
+			unmapFile(data)
+		}
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	S.mapped = mapped
+	return S, nil
+}
+
+// ReadSnapshot reads a binary snapshot into memory and verifies it
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	S, err := loadSnapshot(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := S.Verify(); err != nil {
+		return nil, err
+	}
+	return S, nil
+}
+
+// Verify checks the checksum of the data and that the
+// graph is valid, which requires reading the whole snapshot
+func (S *Snapshot) Verify() error {
+	if crc32.Checksum(S.data[snapshotHeader:], castagnoli) != S.checksum {
+		return ErrChecksum
+	}
+	C := S.CSR
+	if C.Offsets[0] != 0 || C.Offsets[C.V] != len(C.Targets) {
+		return fmt.Errorf("offsets: %w", ErrSnapshot)
+	}
+	for v := 0; v < C.V; v++ {
+		if C.Offsets[v] > C.Offsets[v+1] {
+			return fmt.Errorf("offsets of vertex %d: %w", v, ErrSnapshot)
+		}
+	}
+	// the rows must be sorted (HasEdge and Weight search them) and
+	// the number of edges must match (undirected edges are stored
+	// twice, loops only once, like in NewCSRParallel)
+	E := 0
+	for v := 0; v < C.V; v++ {
+		row := C.Targets[C.Offsets[v]:C.Offsets[v+1]]
+		for i, t := range row {
+			if t < 0 || t >= C.V {
+				return fmt.Errorf("target %d: %w", t, ErrVertexRange)
+			}
+			if i > 0 && t <= row[i-1] {
+				return fmt.Errorf("row of vertex %d is not sorted: %w", v, ErrSnapshot)
+			}
+			if C.directed || t >= v {
+				E++
+			}
+		}
+	}
+	if E != C.E {
+		return fmt.Errorf("%d edges, want %d: %w", C.E, E, ErrSnapshot)
+	}
+	return nil
+}
+
+// Close releases the memory of the snapshot,
+// the graph can't be used anymore afterwards
+func (S *Snapshot) Close() error {
+	data, mapped := S.data, S.mapped
+	S.CSR, S.Coords, S.data, S.mapped = nil, nil, nil, false
+	if mapped {
+		return unmapFile(data)
+	}
+	return nil
+}
+
+// set up the graph of a snapshot from its content
+func loadSnapshot(data []byte) (*Snapshot, error) {
+	if len(data) < snapshotHeader || !bytes.Equal(data[:8], []byte(snapshotMagic)) {
+		return nil, fmt.Errorf("missing header: %w", ErrSnapshot)
+	}
+	header := data[:snapshotHeader]
+	if crc32.Checksum(header[:44], castagnoli) != binary.LittleEndian.Uint32(header[44:]) {
+		return nil, fmt.Errorf("header: %w", ErrChecksum)
+	}
+	if version := binary.LittleEndian.Uint32(header[8:]); version != snapshotVersion {
+		return nil, fmt.Errorf("unsupported version %d: %w", version, ErrSnapshot)
+	}
+	flags := binary.LittleEndian.Uint32(header[12:])
+	V := binary.LittleEndian.Uint64(header[16:])
+	E := binary.LittleEndian.Uint64(header[24:])
+	n := binary.LittleEndian.Uint64(header[32:])
+
+	// the size of the file must match, the numbers are limited
+	// to avoid overflows in the calculation of the size
+	const limit = 1 << 40
+	if V >= limit || E >= limit || n >= limit {
+		return nil, fmt.Errorf("size of the graph: %w", ErrSnapshot)
+	}
+	size := snapshotHeader + 8*(V+1) + 16*n
+	if flags&snapshotCoordinates != 0 {
+		size += 16 * V
+	}
+	if uint64(len(data)) != size {
+		return nil, fmt.Errorf("file size %d, want %d: %w", len(data), size, ErrSnapshot)
+	}
+
+	S := &Snapshot{data: data, checksum: binary.LittleEndian.Uint32(header[40:])}
+	S.CSR = new(CSR)
+	S.V, S.E = int(V), int(E)
+	S.CSR.directed = flags&snapshotDirected != 0
+	pos := snapshotHeader
+	S.Offsets = snapshotInts(data[pos:], int(V)+1)
+	pos += 8 * (int(V) + 1)
+	S.Targets = snapshotInts(data[pos:], int(n))
+	pos += 8 * int(n)
+	S.Weights = snapshotFloats(data[pos:], int(n))
+	pos += 8 * int(n)
+	if flags&snapshotCoordinates != 0 {
+		xy := snapshotFloats(data[pos:], 2*int(V))
+		if len(xy) > 0 {
+			S.Coords = unsafe.Slice((*Point)(unsafe.Pointer(&xy[0])), V)
+		} else {
+			S.Coords = []Point{}
+		}
+	}
+	return S, nil
+}
+
+// can the data be used directly as []int and []float64?
+var nativeSnapshot = strconv.IntSize == 64 && binary.NativeEndian.Uint16([]byte{1, 0}) == 1
+
+// snapshotInts returns the first n integers of the data,
+// using the same memory if possible
+func snapshotInts(data []byte, n int) []int {
+	if n == 0 {
+		return []int{}
+	}
+	if nativeSnapshot && uintptr(unsafe.Pointer(&data[0]))%8 == 0 {
+		return unsafe.Slice((*int)(unsafe.Pointer(&data[0])), n)
+	}
+	x := make([]int, n)
+	for i := range x {
+		x[i] = int(int64(binary.LittleEndian.Uint64(data[8*i:])))
+	}
+	return x
+}
+
+// snapshotFloats returns the first n floats of the data,
+// using the same memory if possible
+func snapshotFloats(data []byte, n int) []float64 {
+	if n == 0 {
+		return []float64{}
+	}
+	if nativeSnapshot && uintptr(unsafe.Pointer(&data[0]))%8 == 0 {
+		return unsafe.Slice((*float64)(unsafe.Pointer(&data[0])), n)
+	}
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:]))
+	}
+	return x
+}
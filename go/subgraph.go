@@ -0,0 +1,97 @@
+/* This file contains routines to work on a part of a graph,
+either by extracting a (smaller) subgraph or by a filtered
+view, which hides vertices and edges without copying anything. */
+
+package shortestpath
+
+import "fmt"
+
+// Subgraph returns a new graph containing the given vertices and those
+// edges between them for which keep returns true (all edges if keep is nil).
+// The vertices are renumbered in the given order, the returned table
+// contains the new index of each old vertex (or -1 if it is not included).
+// Vertex labels and the policy for parallel edges are kept.
+func (G *Graph) Subgraph(vertices []int, keep func(e Edge) bool) (*Graph, []int, error) {
+	remap := make([]int, G.V)
+	for i := range remap {
+		remap[i] = -1
+	}
+	for i, v := range vertices {
+		if !G.hasVertex(v) {
+			return nil, nil, fmt.Errorf("vertex %d of the subgraph: %w", v, ErrVertexRange)
+		}
+		if remap[v] != -1 {
+			return nil, nil, fmt.Errorf("vertex %d is part of the subgraph twice", v)
+		}
+		remap[v] = i
+	}
+
+	S := NewGraph()
+	S.directed = G.directed
+	S.parallel = G.parallel
+	S.SetOrder(len(vertices))
+	for _, v := range vertices {
+		for u, w := range G.Adjacent(v) {
+			e := Edge{v, u, w}
+			if remap[u] == -1 || (!G.directed && u < v) || (keep != nil && !keep(e)) {
+				continue
+			}
+			S.AddEdge(remap[v], remap[u], w)
+		}
+		if name := G.Label(v); name != "" {
+			S.SetLabel(remap[v], name)
+		}
+	}
+	return S, remap, nil
+}
+
+// Induced returns the subgraph induced by the given vertices, i.e.,
+// containing these vertices and all edges between them.
+// See Subgraph for the renumbering of the vertices.
+func (G *Graph) Induced(vertices []int) (*Graph, []int, error) {
+	return G.Subgraph(vertices, nil)
+}
+
+// View is a filtered view of a graph. Only the vertices and edges that
+// are accepted by the filters are visible to the shortest-path routines,
+// but nothing is copied and the vertices keep their indices.
+// Hidden vertices are simply unreachable.
+type View[W Number] struct {
+	G      Weighted[W]              // the underlying graph
+	Vertex func(v int) bool         // vertex filter (nil accepts all vertices)
+	Edge   func(u, v int, w W) bool // edge filter (nil accepts all edges)
+}
+
+// Filter returns a filtered view of G, e.g. to route only on
+// roads of a certain class or without closed vertices
+func Filter[W Number](G Weighted[W], vertex func(v int) bool, edge func(u, v int, w W) bool) *View[W] {
+	return &View[W]{G, vertex, edge}
+}
+
+// Without returns a view of G in which the given vertices are hidden
+func Without[W Number](G Weighted[W], vertices ...int) *View[W] {
+	hidden := make(map[int]bool, len(vertices))
+	for _, v := range vertices {
+		hidden[v] = true
+	}
+	return Filter(G, func(v int) bool { return !hidden[v] }, nil)
+}
+
+// Order returns the number of vertices of the underlying graph
+func (F *View[W]) Order() int { return F.G.Order() }
+
+// VisitEdges calls fn for every visible edge leaving vertex u
+func (F *View[W]) VisitEdges(u int, fn func(v int, w W)) {
+	if F.Vertex != nil && !F.Vertex(u) {
+		return
+	}
+	F.G.VisitEdges(u, func(v int, w W) {
+		if F.Vertex != nil && !F.Vertex(v) {
+			return
+		}
+		if F.Edge != nil && !F.Edge(u, v, w) {
+			return
+		}
+		fn(v, w)
+	})
+}
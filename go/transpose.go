@@ -0,0 +1,73 @@
+/* This file contains routines to reverse a directed graph
+and the single-target variants of the shortest-path routines,
+which find the shortest paths from all vertices to a destination
+by running the search backwards on the reversed graph. */
+
+package shortestpath
+
+// Transpose returns the transposed (reversed) graph, in which every
+// directed edge (v1,v2) becomes (v2,v1). For undirected graphs a copy is returned.
+// Vertex labels and the policy for parallel edges are kept.
+func (G *Graph) Transpose() *Graph {
+	T := NewGraph()
+	T.directed = G.directed
+	T.parallel = G.parallel
+	T.SetOrder(G.V)
+	for e := range G.Edges() {
+		T.AddEdge(e.To, e.From, e.Weight)
+	}
+	for v := 0; v < G.V; v++ {
+		if name := G.Label(v); name != "" {
+			T.SetLabel(v, name)
+		}
+		if G.Deleted(v) {
+			T.DelVertex(v)
+		}
+	}
+	return T
+}
+
+// Transpose returns the transposed (reversed) graph, in which every
+// directed edge (v1,v2) becomes (v2,v1). For undirected graphs a copy is returned.
+func (C *CSROf[W]) Transpose() *CSROf[W] {
+	var edges []EdgeOf[W]
+	for e := range C.Edges() {
+		edges = append(edges, EdgeOf[W]{e.To, e.From, e.Weight})
+	}
+	return NewCSROf(C.V, C.directed, edges)
+}
+
+// Reverse returns the reversed graph of any graph G as a directed CSR graph,
+// i.e., the edges leaving a vertex v are the edges entering v in G
+func Reverse[W Number](G Weighted[W]) *CSROf[W] {
+	var edges []EdgeOf[W]
+	for e := range Arcs(G) {
+		edges = append(edges, EdgeOf[W]{e.To, e.From, e.Weight})
+	}
+	C, _ := NewCSRParallel(G.Order(), true, edges, KeepMin)
+	return C
+}
+
+// DijkstraTo finds the shortest paths from ALL vertices to a given
+// target using Dijkstra's algorithm. It returns the distances to the
+// target and the successor of each vertex on its path to the target.
+func DijkstraTo(G Interface, target int) ([]float64, []int) {
+	return Dijkstra(Reverse(G), target)
+}
+
+// BellmanFordTo finds the shortest paths from ALL vertices to a given
+// target using the Bellman-Ford algorithm. It returns the distances to the
+// target and the successor of each vertex on its path to the target.
+func BellmanFordTo(G Interface, target int) ([]float64, []int) {
+	return BellmanFord(Reverse(G), target)
+}
+
+// GetPathTo follows the successors next returned by DijkstraTo or
+// BellmanFordTo and constructs the shortest possible path from start to the target.
+func GetPathTo(start, target int, next []int) ([]int, error) {
+	path, err := GetPathD(target, start, next)
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, err
+}
@@ -0,0 +1,39 @@
+/* This file contains the generic weight types.
+Graphs with integer edge weights (e.g. costs in cents or
+travel times in seconds) can use an integer type for the
+weights and get exact arithmetic, opposed to float64. */
+
+package shortestpath
+
+import (
+	"math"
+	"unsafe"
+)
+
+// Number is the constraint for the type of the edge weights
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// Weighted is what the shortest-path routines need to know about
+// a graph with edge weights of type W: its order and the (weighted)
+// edges leaving each vertex. CSROf[W] implements it.
+type Weighted[W Number] interface {
+	// Order returns the number of vertices, which are numbered 0 to Order()-1
+	Order() int
+	// VisitEdges calls fn for every edge leaving vertex u with
+	// the neighbour v and the weight w of the edge.
+	// For undirected graphs these are all edges of u.
+	VisitEdges(u int, fn func(v int, w W))
+}
+
+// Infinity returns the distance that is used for unreachable vertices,
+// +Inf for floating point weights and the largest value of W for integers.
+func Infinity[W Number]() W {
+	h := 0.5
+	if W(h) != 0 { // floating point type
+		return W(math.Inf(0))
+	}
+	bits := 8 * unsafe.Sizeof(W(0))
+	return W(int64(^uint64(0) >> (65 - bits)))
+}